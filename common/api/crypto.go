@@ -3,11 +3,13 @@ package api
 import (
 	"github.com/Grivn/phalanx/common/protos"
 	"github.com/Grivn/phalanx/common/types"
+	"github.com/Grivn/phalanx/external"
 )
 
 type Crypto interface {
 	Signer
 	Verifier
+	Aggregator
 }
 
 type Signer interface {
@@ -17,4 +19,23 @@ type Signer interface {
 type Verifier interface {
 	PublicVerify(cert *protos.Certification, hash types.Hash, nodeID uint64) error
 	VerifyProofCerts(digest types.Hash, pc *protos.QuorumCert, quorum int) error
+
+	// UpdateVerifiers replaces the public keys with the ones for given members, and the members
+	// without a new public key would keep the one they have used in previous epoch.
+	UpdateVerifiers(members []uint64, keys map[uint64]external.PublicKey) error
+
+	// VerifyMisbehavior checks if the proof contains two valid conflicting messages generated by the offender.
+	VerifyMisbehavior(proof *protos.MisbehaviorProof, quorum int) error
+}
+
+type Aggregator interface {
+	// Aggregated returns whether the quorum-certs should be generated in compact mode.
+	Aggregated() bool
+
+	// Aggregate compresses the individual certifications on digest into a quorum-cert with a signer bitmap
+	// and an aggregated signature.
+	Aggregate(digest types.Hash, certs []*protos.CertEntry) (*protos.QuorumCert, error)
+
+	// VerifyAggregate verifies the quorum-cert in compact mode with one aggregated signature check.
+	VerifyAggregate(digest types.Hash, pc *protos.QuorumCert, quorum int) error
 }
@@ -1,12 +1,18 @@
 package api
 
-import "github.com/Grivn/phalanx/common/types"
+import (
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
+)
 
 type Finality interface {
 	Runner
 
 	// CommitStream is used to commit the partial order stream.
 	CommitStream(qStream types.QueryStream)
+
+	// Reconfigure is used to commit the membership change, which takes effect after the streams committed before it.
+	Reconfigure(reconf *protos.Reconfiguration)
 }
 
 //=============================================== Command Reader for Finality =====================================================
@@ -17,6 +23,8 @@ type CommandRecorder interface {
 	LeafManager
 	PriorityManager
 	QueueManager
+	CheckpointManager
+	EpochManager
 }
 
 type InfoReader interface {
@@ -41,11 +49,15 @@ type InfoStatus interface {
 	QuorumStatus(commandD string)
 
 	// CommittedStatus set commands into committed status.
-	CommittedStatus(commandD string)
+	CommittedStatus(command *protos.Command)
 
 	// IsCommitted returns if current command has been committed.
 	IsCommitted(commandD string) bool
 
+	// IsExecuted returns if the command current partial order refers to has been committed, which is checked with
+	// both the command digest and the sequence number watermark of its client.
+	IsExecuted(oInfo types.OrderInfo) bool
+
 	// IsQuorum returns if we have received quorum partial orders for this command.
 	IsQuorum(commandD string) bool
 }
@@ -80,12 +92,29 @@ type QueueManager interface {
 	PickQuorumInfo() *types.CommandInfo
 }
 
+type CheckpointManager interface {
+	// Checkpoint is used to garbage collect the committed commands before the previous checkpoint.
+	Checkpoint()
+}
+
+type EpochManager interface {
+	// Reconfigure is used to rebuild the order queues and recompute the thresholds for the new membership.
+	Reconfigure(members []uint64)
+}
+
 //================================== Cyclic Scanner ==============================================
 
 type CondorcetScanner interface {
+	// HasCyclic returns if there is a precedence cycle containing the target command.
 	HasCyclic() bool
+
+	// Cycle returns the commands in the precedence cycle of target command sorted with trusted timestamp.
+	Cycle() types.CommandStream
 }
 
 type Interceptor interface {
 	SelectToCommit(barrier types.CommandStream) types.CommandStream
+
+	// CycleSizes returns the size of each precedence cycle in the latest selected stream.
+	CycleSizes() []int
 }
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"context"
+	"time"
+
 	"github.com/Grivn/phalanx/common/protos"
 	"github.com/Grivn/phalanx/common/types"
 )
@@ -11,6 +14,10 @@ type MetaPool interface {
 	MetaReader
 	MetaCommitter
 	MetaConsensus
+	MissingFetcher
+	MetaCheckpoint
+	MetaEpoch
+	MisbehaviorProcessor
 }
 
 type LogManager interface {
@@ -21,7 +28,8 @@ type LogManager interface {
 type LocalLog interface {
 	// ProcessCommand is used to process command received from clients.
 	// We would like to assign a sequence number for such a command and generate a pre-order message.
-	ProcessCommand(command *protos.Command)
+	// It returns types.ErrOverload once the command has been refused with overload policy.
+	ProcessCommand(command *protos.Command) error
 
 	// ProcessVote is used to process the vote message from others.
 	// It could aggregate a agg-signature for one pre-order and generate an order message for one command.
@@ -43,10 +51,18 @@ type RemoteLog interface {
 }
 
 type MetaReader interface {
-	// ReadCommand reads raw command from meta pool.
-	ReadCommand(commandD string) *protos.Command
-
-	// ReadPartials reads partial orders according to query stream.
+	// ReadCommand reads raw command from meta pool, it blocks until the command has been received,
+	// and returns nil if the meta pool has been closed.
+	// The referrers are the replicas whose partial orders have referred to this command,
+	// and we would like to fetch the command from them if we haven't received it.
+	ReadCommand(commandD string, referrers []uint64) *protos.Command
+
+	// PeekCommand reads raw command as ReadCommand, but it wouldn't be counted as a read of the ordering strategies,
+	// which is used by the shadow strategies, and the committed commands could be read until garbage collected.
+	PeekCommand(commandD string, referrers []uint64) *protos.Command
+
+	// ReadPartials reads partial orders according to query stream, it blocks until all of them have been received,
+	// and returns nil if the meta pool has been closed.
 	ReadPartials(qStream types.QueryStream) []*protos.PartialOrder
 }
 
@@ -64,6 +80,49 @@ type MetaConsensus interface {
 	VerifyProposal(batch *protos.PartialOrderBatch) (types.QueryStream, error)
 }
 
+type MissingFetcher interface {
+	// ProcessFetchPartial is used to process the request from others to fetch a partial order
+	// which has been committed but hasn't been received by the requester.
+	ProcessFetchPartial(fetch *protos.FetchPartial) error
+
+	// ProcessReturnPartial is used to process the partial order returned by others in fetch-missing process.
+	ProcessReturnPartial(pOrder *protos.PartialOrder) error
+
+	// ProcessFetchCommand is used to process the request from others to fetch a command
+	// which has been referred by our partial orders.
+	ProcessFetchCommand(fetch *protos.FetchCommand) error
+
+	// ProcessReturnCommand is used to process the command returned by others in fetch-missing process.
+	ProcessReturnCommand(command *protos.Command) error
+}
+
+type MetaCheckpoint interface {
+	// Checkpoint notifies meta pool the stable checkpoint, so that the states below it could be garbage collected.
+	Checkpoint(checkpoint types.Checkpoint)
+}
+
+type MetaEpoch interface {
+	// ProposeReconfiguration records the membership change which would be proposed with the next partial order batch.
+	ProposeReconfiguration(reconf *protos.Reconfiguration) error
+
+	// SignReconfiguration generates the signature of current node to authorize the membership change.
+	SignReconfiguration(reconf *protos.Reconfiguration) (*protos.Certification, error)
+
+	// Members returns the current epoch number and the sorted identifiers of participants.
+	Members() (uint64, []uint64)
+}
+
+type MisbehaviorProcessor interface {
+	// ProcessMisbehavior is used to process the proof of misbehavior detected by others.
+	ProcessMisbehavior(proof *protos.MisbehaviorProof) error
+}
+
+// MisbehaviorReporter is used to report the proof of misbehavior we have detected or received.
+type MisbehaviorReporter interface {
+	// ReportMisbehavior records the verified proof, and notifies others and the embedding chain if it's a new one.
+	ReportMisbehavior(proof *protos.MisbehaviorProof)
+}
+
 //==================================== instance for meta pool =============================================
 
 // ClientInstance is used to process commands info generated by specific client.
@@ -72,7 +131,16 @@ type ClientInstance interface {
 	Commit(seqNo uint64) int
 
 	// Append is used to notify the latest received command from current client.
-	Append(command *protos.Command) int
+	// It returns types.ErrOverload once the command couldn't be proposed towards log-manager with overload policy,
+	// or an older command has been dropped to make room for it.
+	Append(command *protos.Command) (int, error)
+
+	// Gap returns the missing sequence number which stalls current client and the time since when it has stalled.
+	Gap() (uint64, time.Time, bool)
+
+	// Skip gives up the missing command once the following commands of current client have been committed,
+	// which means quorum replicas have moved past it, and it returns false if the command couldn't be skipped.
+	Skip(seqNo uint64) (bool, error)
 }
 
 // ReplicaInstance is used to process partial orders generated by each participant.
@@ -85,19 +153,87 @@ type ReplicaInstance interface {
 
 	// ReceivePartial is used to process the partial order message from current replica.
 	ReceivePartial(pOrder *protos.PartialOrder) error
+
+	// ReceiveFetchedPartial is used to process the partial order of current replica we have fetched from others.
+	ReceiveFetchedPartial(pOrder *protos.PartialOrder) error
+
+	// UpdateQuorum is used to update the quorum size once the membership has been changed.
+	UpdateQuorum(quorum int)
+
+	// Recover is used to restore the status of current replica with the persisted vote or partial order.
+	Recover(entry *protos.WALEntry) error
+
+	// Snapshot returns the entries to restore current replica, and the partial orders below the committed sequence
+	// number are replaced by a base one, which is only used to link the following partial orders.
+	Snapshot(committed uint64) []*protos.WALEntry
+}
+
+//================================== batching for meta pool ========================================
+
+// BatchPolicy is used to decide when the commands in waiting list should be selected into a pre-order.
+type BatchPolicy interface {
+	// MaxSize returns the max number of commands in one pre-order, and a non-positive one means unbounded.
+	// A pre-order would be generated at once when the waiting list has reached it.
+	MaxSize() int
+
+	// Delay returns the max interval to wait for more commands since the first one in waiting list has arrived.
+	Delay() time.Duration
+
+	// ObserveArrival records the arrival of a command.
+	ObserveArrival()
+
+	// ObserveQuorum records the interval from the generation of a pre-order to the time it has collected quorum votes.
+	ObserveQuorum(latency time.Duration)
 }
 
 //================================== tracker for meta pool ========================================
 
 // CommandTracker is used to record received commands.
 type CommandTracker interface {
-	RecordCommand(command *protos.Command)
+	// RecordCommand validates the command before recording it, and returns the typed error for invalid one.
+	// It returns types.ErrClientEquivocation for the command which conflicts with a recorded one of the same client
+	// and sequence number, and the conflicting one would still be recorded as evidence.
+	RecordCommand(command *protos.Command) error
 	ReadCommand(digest string) *protos.Command
+	WaitCommand(ctx context.Context, digest string) *protos.Command
+
+	// PeekCommand blocks until the command has been recorded as WaitCommand, but it doesn't count the read.
+	PeekCommand(ctx context.Context, digest string) *protos.Command
+	GetCommand(digest string) *protos.Command
+
+	// GetCommandByIndex returns the command with the identifier of client and sequence number.
+	GetCommandByIndex(idx types.QueryIndex) *protos.Command
+	ReceivedTime(digest string) (int64, bool)
+	Checkpoint()
 }
 
 // PartialTracker is used to record received partial orders.
 type PartialTracker interface {
 	RecordPartial(pOrder *protos.PartialOrder)
 	ReadPartial(idx types.QueryIndex) *protos.PartialOrder
+	WaitPartial(ctx context.Context, idx types.QueryIndex) *protos.PartialOrder
+	GetPartial(idx types.QueryIndex) *protos.PartialOrder
 	IsExist(idx types.QueryIndex) bool
+	Checkpoint(watermarks map[uint64]uint64)
+}
+
+//================================== write-ahead log for meta pool ========================================
+
+// WriteAheadLog is used to persist the messages we have generated, so that we could recover after a restart.
+type WriteAheadLog interface {
+	// Append persists the entry before return.
+	Append(entry *protos.WALEntry) error
+
+	// Replay reads the persisted entries in order and processes them with given function.
+	Replay(process func(entry *protos.WALEntry) error) error
+
+	// Offset returns the size of persisted entries, which could be used to mark the entries kept in compaction.
+	Offset() int64
+
+	// Compact replaces the entries persisted before offset with the given ones, and the entries appended after
+	// offset would be kept behind them.
+	Compact(offset int64, entries []*protos.WALEntry) error
+
+	// Close closes the write-ahead log.
+	Close() error
 }
@@ -6,5 +6,6 @@ type Proposer interface {
 	Runner
 
 	// ProcessTransaction is used to process transactions received by current node.
-	ProcessTransaction(tx *protos.Transaction)
+	// It returns types.ErrOverload once the transaction has been refused with overload policy.
+	ProcessTransaction(tx *protos.Transaction) error
 }
@@ -0,0 +1,229 @@
+package mocks
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
+	"github.com/Grivn/phalanx/external"
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+const (
+	// BLS12_381 is supported for crypto algorithms whose signatures could be aggregated.
+	BLS12_381 = "BLS12_381"
+
+	// blsDomain is the domain separation tag for hashing messages into G1.
+	blsDomain = "PHALANX-BLS-SIG-BLS12381G1_XMD:SHA-256_SSWU_RO_"
+
+	// blsPopDomain is the domain separation tag for hashing public keys into G1 to generate proof-of-possession,
+	// so that a proof couldn't be used as the signature of any message.
+	blsPopDomain = "PHALANX-BLS-POP-BLS12381G1_XMD:SHA-256_SSWU_RO_"
+)
+
+//==================================== create bls validator =============================================
+
+// GenerateBLSKeys is used to init the public/private keys for validator with the aggregatable signature scheme.
+// The keys are derived from the node identifiers, so that every node could generate the same public keys.
+// Such a static generation is only used for test, and the keys registered with reconfiguration should carry
+// a proof-of-possession generated by GenerateBLSPossession to defend the rogue key attack on aggregated signatures.
+func GenerateBLSKeys(author uint64, count int) (external.PrivateKey, map[uint64]external.PublicKey, error) {
+	var privKey external.PrivateKey
+	pubKeys := make(map[uint64]external.PublicKey, count)
+	for i := 0; i < count; i++ {
+		id := uint64(i + 1)
+		pair := generateBLSKeyHelper(id)
+		pubKeys[id] = pair.PublicKey()
+		if id == author {
+			privKey = pair
+		}
+	}
+	return privKey, pubKeys, nil
+}
+
+func generateBLSKeyHelper(id uint64) *blsPrivateKey {
+	seed := make([]byte, 8)
+	binary.BigEndian.PutUint64(seed, id)
+	raw := sha256.Sum256(append([]byte(blsDomain), seed...))
+
+	// clear the top bits to make sure the secret is smaller than the order of group.
+	raw[0] &= 0x3f
+	secret := bls12381.NewFr().FromBytes(raw[:])
+
+	g2 := bls12381.NewG2()
+	pub := g2.New()
+	g2.MulScalar(pub, g2.One(), secret)
+	return &blsPrivateKey{secret: secret, pub: &blsPublicKey{point: pub}}
+}
+
+// ================================= bls implementation ====================================
+
+// blsPrivateKey signs the message hashed into G1, and the related public key is a point of G2.
+type blsPrivateKey struct {
+	secret *bls12381.Fr
+	pub    *blsPublicKey
+}
+
+type blsPublicKey struct {
+	point *bls12381.PointG2
+}
+
+// PublicKey returns the public key.
+func (priv *blsPrivateKey) PublicKey() external.PublicKey {
+	return priv.pub
+}
+
+// Algorithm returns the signing algorithm related to the private key.
+func (priv *blsPrivateKey) Algorithm() string {
+	return BLS12_381
+}
+
+// Sign generates the signature with one compressed G1 point.
+func (priv *blsPrivateKey) Sign(hash types.Hash) (*protos.Certification, error) {
+	return priv.sign(hash, blsDomain)
+}
+
+func (priv *blsPrivateKey) sign(message []byte, domain string) (*protos.Certification, error) {
+	g1 := bls12381.NewG1()
+	point, err := g1.HashToCurve(message, []byte(domain))
+	if err != nil {
+		return nil, err
+	}
+	g1.MulScalar(point, point, priv.secret)
+	return &protos.Certification{Signatures: [][]byte{g1.ToCompressed(point)}}, nil
+}
+
+// GenerateBLSPossession generates the proof-of-possession for the private key generated by GenerateBLSKeys,
+// which signs the compressed public key with a separated domain.
+func GenerateBLSPossession(priv external.PrivateKey) (*protos.Certification, error) {
+	key, ok := priv.(*blsPrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid private key algorithm %s", priv.Algorithm())
+	}
+	return key.sign(bls12381.NewG2().ToCompressed(key.pub.point), blsPopDomain)
+}
+
+// Algorithm returns the signing algorithm related to the public key.
+func (pub *blsPublicKey) Algorithm() string {
+	return BLS12_381
+}
+
+// Verify verifies a signature of an input message using the provided hasher.
+func (pub *blsPublicKey) Verify(cert *protos.Certification, hash types.Hash) error {
+	if cert == nil || len(cert.Signatures) != 1 {
+		return errors.New("invalid signature format")
+	}
+	return verifyBLS(cert.Signatures[0], hash, blsDomain, pub.point)
+}
+
+// VerifyPossession verifies the proof-of-possession generated by GenerateBLSPossession.
+func (pub *blsPublicKey) VerifyPossession(proof *protos.Certification) error {
+	if proof == nil || len(proof.Signatures) != 1 {
+		return errors.New("invalid proof-of-possession format")
+	}
+	return verifyBLS(proof.Signatures[0], bls12381.NewG2().ToCompressed(pub.point), blsPopDomain, pub.point)
+}
+
+// verifyBLS checks e(signature, g2) == e(H(m), key).
+func verifyBLS(signature []byte, message []byte, domain string, key *bls12381.PointG2) error {
+	g1 := bls12381.NewG1()
+	sig, err := g1.FromCompressed(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
+	}
+	if g1.IsZero(sig) || !g1.InCorrectSubgroup(sig) {
+		return errors.New("invalid signature point")
+	}
+
+	point, err := g1.HashToCurve(message, []byte(domain))
+	if err != nil {
+		return err
+	}
+
+	engine := bls12381.NewEngine()
+	engine.AddPair(point, key)
+	engine.AddPairInv(sig, engine.G2.One())
+	if !engine.Check() {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// ================================= bls aggregation ====================================
+
+// blsAggregator aggregates the signatures on the same message by point addition.
+type blsAggregator struct{}
+
+// NewBLSAggregator returns the aggregator for the signatures generated by the keys of GenerateBLSKeys.
+func NewBLSAggregator() external.SignatureAggregator {
+	return &blsAggregator{}
+}
+
+// AggregateSignatures aggregates the signatures into one compressed G1 point.
+func (a *blsAggregator) AggregateSignatures(certs []*protos.Certification) ([]byte, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("nil signatures to aggregate")
+	}
+
+	g1 := bls12381.NewG1()
+	agg := g1.Zero()
+	for _, cert := range certs {
+		if cert == nil || len(cert.Signatures) != 1 {
+			return nil, errors.New("invalid signature format")
+		}
+		sig, err := g1.FromCompressed(cert.Signatures[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature: %s", err)
+		}
+		g1.Add(agg, agg, sig)
+	}
+	return g1.ToCompressed(agg), nil
+}
+
+// VerifyAggregatedSignature verifies the aggregated signature with the sum of public keys of signers,
+// which costs one pairing check no matter how many signatures have been aggregated.
+func (a *blsAggregator) VerifyAggregatedSignature(signature []byte, hash types.Hash, keys []external.PublicKey) error {
+	if len(keys) == 0 {
+		return errors.New("nil public keys to verify")
+	}
+
+	g2 := bls12381.NewG2()
+	agg := g2.Zero()
+	for _, key := range keys {
+		pub, ok := key.(*blsPublicKey)
+		if !ok {
+			return fmt.Errorf("invalid public key algorithm %s", key.Algorithm())
+		}
+		g2.Add(agg, agg, pub.point)
+	}
+	if g2.IsZero(agg) {
+		return errors.New("invalid aggregated public key")
+	}
+	return verifyBLS(signature, hash, blsDomain, agg)
+}
+
+// ================================= public key encoding ====================================
+
+// blsDecoder is used to decode the encoded bls public keys.
+type blsDecoder struct{}
+
+// NewBLSKeyDecoder returns the decoder for the public keys generated by GenerateBLSKeys.
+func NewBLSKeyDecoder() external.PublicKeyDecoder {
+	return &blsDecoder{}
+}
+
+// DecodePublicKey decodes the public key from raw bytes.
+func (d *blsDecoder) DecodePublicKey(raw []byte) (external.PublicKey, error) {
+	g2 := bls12381.NewG2()
+	point, err := g2.FromCompressed(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %s", err)
+	}
+	if g2.IsZero(point) || !g2.InCorrectSubgroup(point) {
+		return nil, errors.New("invalid public key point")
+	}
+	return &blsPublicKey{point: point}, nil
+}
@@ -5,14 +5,12 @@ import (
 
 	"github.com/Grivn/phalanx/common/protos"
 	"github.com/Grivn/phalanx/common/types"
-
-	"github.com/gogo/protobuf/proto"
 )
 
 func NewTransaction() *protos.Transaction {
 	payload := make([]byte, 4)
 	rand.Read(payload)
-	return &protos.Transaction{Hash: types.CalculatePayloadHash(payload, 0), Payload: payload}
+	return &protos.Transaction{Hash: types.CalculatePayloadHash(types.DefaultHasher(), payload, 0), Payload: payload}
 }
 
 func NewCommand() *protos.Command {
@@ -20,9 +18,7 @@ func NewCommand() *protos.Command {
 	txList := []*protos.Transaction{tx}
 	hashList := []string{tx.Hash}
 	command := &protos.Command{Content: txList, HashList: hashList}
-
-	payload, _ := proto.Marshal(command)
-	command.Digest = types.CalculatePayloadHash(payload, 0)
+	command.Digest, _ = types.CalculateCommandDigest(types.DefaultHasher(), command)
 
 	return command
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Grivn/phalanx/common/protos"
 	"github.com/Grivn/phalanx/common/types"
+	bls12381 "github.com/kilic/bls12-381"
 )
 
 const (
@@ -124,3 +125,35 @@ func signatureToECDSA(cert *protos.Certification) ecdsaSignature {
 	ecdsaSig.s = &s
 	return *ecdsaSig
 }
+
+// ================================= public key encoding ====================================
+
+// ecdsaP256Decoder is used to decode the encoded ecdsa public keys.
+type ecdsaP256Decoder struct{}
+
+// NewKeyDecoder returns the decoder for the public keys generated by GenerateKeys.
+func NewKeyDecoder() external.PublicKeyDecoder {
+	return &ecdsaP256Decoder{}
+}
+
+// DecodePublicKey decodes the public key from raw bytes.
+func (d *ecdsaP256Decoder) DecodePublicKey(raw []byte) (external.PublicKey, error) {
+	curve := elliptic.P256()
+	x, y := elliptic.Unmarshal(curve, raw)
+	if x == nil {
+		return nil, errors.New("invalid public key")
+	}
+	return &ecdsaP256PublicKey{SignAlg: ECDSA_P256, PublicKey: ecdsa.PublicKey{Curve: curve, X: x, Y: y}}, nil
+}
+
+// EncodePublicKey encodes the public key generated by GenerateKeys or GenerateBLSKeys into raw bytes.
+func EncodePublicKey(pub external.PublicKey) ([]byte, error) {
+	switch key := pub.(type) {
+	case *ecdsaP256PublicKey:
+		return elliptic.Marshal(key.PublicKey.Curve, key.PublicKey.X, key.PublicKey.Y), nil
+	case *blsPublicKey:
+		return bls12381.NewG2().ToCompressed(key.point), nil
+	default:
+		return nil, fmt.Errorf("invalid public key algorithm %s", pub.Algorithm())
+	}
+}
@@ -31,7 +31,7 @@ func (exe *executor) CommandExecution(block types.InnerBlock, seqNo uint64) {
 	}
 
 	exe.count += len(command.Content)
-	exe.hash = types.CalculateListHash(list, 0)
+	exe.hash = types.CalculateListHash(types.DefaultHasher(), list, 0)
 
 	exe.fileLogger.Infof("Author %d, FrontNo %d, Safe %v, Block Number %d, total len %d, Hash: %s, from Command %s",
 		exe.author, block.FrontNo, block.Safe, seqNo, exe.count, exe.hash, command.Format())
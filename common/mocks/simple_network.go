@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/Grivn/phalanx/common/protos"
@@ -13,6 +14,10 @@ type SimpleNetwork struct {
 	networkC map[uint64]chan *protos.ConsensusMessage
 	commandC map[uint64]chan *protos.Command
 	logger   external.Logger
+
+	// members are the receivers of broadcast messages, and nil means every node in network.
+	mutex   sync.RWMutex
+	members map[uint64]bool
 }
 
 func NewSimpleNetwork(networkC map[uint64]chan *protos.ConsensusMessage, commandC map[uint64]chan *protos.Command, logger external.Logger, async bool) *SimpleNetwork {
@@ -43,8 +48,27 @@ func (net *SimpleNetwork) BroadcastCommand(command *protos.Command) {
 	go net.sendCommand(command)
 }
 
+// UpdateMembers is used to change the participants who would receive the broadcast messages.
+func (net *SimpleNetwork) UpdateMembers(members []uint64) {
+	set := make(map[uint64]bool, len(members))
+	for _, id := range members {
+		set[id] = true
+	}
+
+	net.mutex.Lock()
+	defer net.mutex.Unlock()
+	net.members = set
+}
+
 func (net *SimpleNetwork) broadcast(message *protos.ConsensusMessage) {
-	for _, ch := range net.networkC {
+	net.mutex.RLock()
+	members := net.members
+	net.mutex.RUnlock()
+
+	for id, ch := range net.networkC {
+		if members != nil && !members[id] {
+			continue
+		}
 		ch <- message
 	}
 }
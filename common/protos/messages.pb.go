@@ -8,6 +8,7 @@ import (
 	proto "github.com/gogo/protobuf/proto"
 	io "io"
 	math "math"
+	math_bits "math/bits"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -19,27 +20,42 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 // MessageType indicates the type of messages.
 type MessageType int32
 
 const (
-	MessageType_PRE_ORDER   MessageType = 0
-	MessageType_VOTE        MessageType = 1
-	MessageType_QUORUM_CERT MessageType = 2
+	MessageType_PRE_ORDER      MessageType = 0
+	MessageType_VOTE           MessageType = 1
+	MessageType_QUORUM_CERT    MessageType = 2
+	MessageType_FETCH_PARTIAL  MessageType = 3
+	MessageType_RETURN_PARTIAL MessageType = 4
+	MessageType_FETCH_COMMAND  MessageType = 5
+	MessageType_RETURN_COMMAND MessageType = 6
+	MessageType_MISBEHAVIOR    MessageType = 7
 )
 
 var MessageType_name = map[int32]string{
 	0: "PRE_ORDER",
 	1: "VOTE",
 	2: "QUORUM_CERT",
+	3: "FETCH_PARTIAL",
+	4: "RETURN_PARTIAL",
+	5: "FETCH_COMMAND",
+	6: "RETURN_COMMAND",
+	7: "MISBEHAVIOR",
 }
 
 var MessageType_value = map[string]int32{
-	"PRE_ORDER":   0,
-	"VOTE":        1,
-	"QUORUM_CERT": 2,
+	"PRE_ORDER":      0,
+	"VOTE":           1,
+	"QUORUM_CERT":    2,
+	"FETCH_PARTIAL":  3,
+	"RETURN_PARTIAL": 4,
+	"FETCH_COMMAND":  5,
+	"RETURN_COMMAND": 6,
+	"MISBEHAVIOR":    7,
 }
 
 func (x MessageType) String() string {
@@ -50,6 +66,79 @@ func (MessageType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_4dc296cbfe5ffcd5, []int{0}
 }
 
+// MisbehaviorType indicates the kind of misbehavior.
+type MisbehaviorType int32
+
+const (
+	// PRE_ORDER_EQUIVOCATION means the offender has signed two pre-orders with the same sequence number.
+	MisbehaviorType_PRE_ORDER_EQUIVOCATION MisbehaviorType = 0
+	// PARTIAL_ORDER_EQUIVOCATION means there are two partial orders with the same sequence number for the offender.
+	MisbehaviorType_PARTIAL_ORDER_EQUIVOCATION MisbehaviorType = 1
+	// CLIENT_EQUIVOCATION means the offender client has issued two commands with the same sequence number.
+	MisbehaviorType_CLIENT_EQUIVOCATION MisbehaviorType = 2
+)
+
+var MisbehaviorType_name = map[int32]string{
+	0: "PRE_ORDER_EQUIVOCATION",
+	1: "PARTIAL_ORDER_EQUIVOCATION",
+	2: "CLIENT_EQUIVOCATION",
+}
+
+var MisbehaviorType_value = map[string]int32{
+	"PRE_ORDER_EQUIVOCATION":     0,
+	"PARTIAL_ORDER_EQUIVOCATION": 1,
+	"CLIENT_EQUIVOCATION":        2,
+}
+
+func (x MisbehaviorType) String() string {
+	return proto.EnumName(MisbehaviorType_name, int32(x))
+}
+
+func (MisbehaviorType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{1}
+}
+
+// WALEntryType indicates the type of write-ahead log entries.
+type WALEntryType int32
+
+const (
+	WALEntryType_WAL_PRE_ORDER       WALEntryType = 0
+	WALEntryType_WAL_VOTE            WALEntryType = 1
+	WALEntryType_WAL_PARTIAL         WALEntryType = 2
+	WALEntryType_WAL_COMMIT          WALEntryType = 3
+	WALEntryType_WAL_RECONFIGURATION WALEntryType = 4
+	WALEntryType_WAL_SNAPSHOT        WALEntryType = 5
+	WALEntryType_WAL_BASE_PARTIAL    WALEntryType = 6
+)
+
+var WALEntryType_name = map[int32]string{
+	0: "WAL_PRE_ORDER",
+	1: "WAL_VOTE",
+	2: "WAL_PARTIAL",
+	3: "WAL_COMMIT",
+	4: "WAL_RECONFIGURATION",
+	5: "WAL_SNAPSHOT",
+	6: "WAL_BASE_PARTIAL",
+}
+
+var WALEntryType_value = map[string]int32{
+	"WAL_PRE_ORDER":       0,
+	"WAL_VOTE":            1,
+	"WAL_PARTIAL":         2,
+	"WAL_COMMIT":          3,
+	"WAL_RECONFIGURATION": 4,
+	"WAL_SNAPSHOT":        5,
+	"WAL_BASE_PARTIAL":    6,
+}
+
+func (x WALEntryType) String() string {
+	return proto.EnumName(WALEntryType_name, int32(x))
+}
+
+func (WALEntryType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{2}
+}
+
 // Transaction is a structure for one instruction.
 type Transaction struct {
 	// Hash is the identifier.
@@ -74,7 +163,7 @@ func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -130,6 +219,8 @@ type Command struct {
 	GTime int64 `protobuf:"varint,6,opt,name=GTime,proto3" json:"GTime,omitempty"`
 	// FrontRunner is used to detect interval relationship front attack, metrics info.
 	FrontRunner *CommandProtoIndex `protobuf:"bytes,7,opt,name=FrontRunner,proto3" json:"FrontRunner,omitempty"`
+	// Signature is generated by the client on digest, which is essential once the client keys have been registered.
+	Signature *Certification `protobuf:"bytes,8,opt,name=Signature,proto3" json:"Signature,omitempty"`
 }
 
 func (m *Command) Reset()         { *m = Command{} }
@@ -146,7 +237,7 @@ func (m *Command) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_Command.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -214,6 +305,13 @@ func (m *Command) GetFrontRunner() *CommandProtoIndex {
 	return nil
 }
 
+func (m *Command) GetSignature() *Certification {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 // CommandProtoIndex indicates the essential index info for command request.
 type CommandProtoIndex struct {
 	// Author indicates the generator current command.
@@ -236,7 +334,7 @@ func (m *CommandProtoIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, e
 		return xxx_messageInfo_CommandProtoIndex.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -295,7 +393,7 @@ func (m *ConsensusMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, er
 		return xxx_messageInfo_ConsensusMessage.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -356,6 +454,12 @@ type PreOrder struct {
 	TimestampList []int64 `protobuf:"varint,5,rep,packed,name=TimestampList,proto3" json:"TimestampList,omitempty"`
 	// ParentDigest indicates the parent pre-order digest.
 	ParentDigest string `protobuf:"bytes,6,opt,name=ParentDigest,proto3" json:"ParentDigest,omitempty"`
+	// Signature is generated by the author on digest, so that a conflicting pre-order could be used as evidence.
+	Signature *Certification `protobuf:"bytes,7,opt,name=Signature,proto3" json:"Signature,omitempty"`
+	// ClientList indicates the clients who have issued the commands in CommandList.
+	ClientList []uint64 `protobuf:"varint,8,rep,packed,name=ClientList,proto3" json:"ClientList,omitempty"`
+	// ClientSeqList indicates the sequence numbers assigned by clients for the commands in CommandList.
+	ClientSeqList []uint64 `protobuf:"varint,9,rep,packed,name=ClientSeqList,proto3" json:"ClientSeqList,omitempty"`
 }
 
 func (m *PreOrder) Reset()         { *m = PreOrder{} }
@@ -372,7 +476,7 @@ func (m *PreOrder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_PreOrder.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -433,6 +537,27 @@ func (m *PreOrder) GetParentDigest() string {
 	return ""
 }
 
+func (m *PreOrder) GetSignature() *Certification {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *PreOrder) GetClientList() []uint64 {
+	if m != nil {
+		return m.ClientList
+	}
+	return nil
+}
+
+func (m *PreOrder) GetClientSeqList() []uint64 {
+	if m != nil {
+		return m.ClientSeqList
+	}
+	return nil
+}
+
 // Certification is used to verify the pre-ordering message on one node.
 type Certification struct {
 	// Signatures are the proof information which is generated by current node, signatures = SIGN(digest).
@@ -453,7 +578,7 @@ func (m *Certification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error
 		return xxx_messageInfo_Certification.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -504,7 +629,7 @@ func (m *Vote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_Vote.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -544,17 +669,77 @@ func (m *Vote) GetCertification() *Certification {
 	return nil
 }
 
+// CertEntry is the signature generated by one participate, which shares the same wire format with a map entry.
+type CertEntry struct {
+	// ID indicates the identifier of signer.
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// Cert is the signature generated by signer.
+	Cert *Certification `protobuf:"bytes,2,opt,name=Cert,proto3" json:"Cert,omitempty"`
+}
+
+func (m *CertEntry) Reset()         { *m = CertEntry{} }
+func (m *CertEntry) String() string { return proto.CompactTextString(m) }
+func (*CertEntry) ProtoMessage()    {}
+func (*CertEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{7}
+}
+func (m *CertEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CertEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CertEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CertEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertEntry.Merge(m, src)
+}
+func (m *CertEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *CertEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertEntry proto.InternalMessageInfo
+
+func (m *CertEntry) GetID() uint64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *CertEntry) GetCert() *Certification {
+	if m != nil {
+		return m.Cert
+	}
+	return nil
+}
+
 // QuorumCert contains the signatures generated by participates which could verify the validation of current message.
 type QuorumCert struct {
-	// Certs are the signatures generated by others.
-	Certs map[uint64]*Certification `protobuf:"bytes,1,rep,name=Certs,proto3" json:"Certs,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Certs are the signatures generated by others, which are sorted by the identifier of signers,
+	// so that the encoding of quorum-cert is canonical.
+	Certs []*CertEntry `protobuf:"bytes,1,rep,name=Certs,proto3" json:"Certs,omitempty"`
+	// Signers is the bitmap of participates whose signatures have been aggregated, the bit i refers to node i.
+	Signers []byte `protobuf:"bytes,2,opt,name=Signers,proto3" json:"Signers,omitempty"`
+	// AggSignature is the aggregated signature in compact mode, and Certs would be empty in such a mode.
+	AggSignature []byte `protobuf:"bytes,3,opt,name=AggSignature,proto3" json:"AggSignature,omitempty"`
 }
 
 func (m *QuorumCert) Reset()         { *m = QuorumCert{} }
 func (m *QuorumCert) String() string { return proto.CompactTextString(m) }
 func (*QuorumCert) ProtoMessage()    {}
 func (*QuorumCert) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4dc296cbfe5ffcd5, []int{7}
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{8}
 }
 func (m *QuorumCert) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -564,7 +749,7 @@ func (m *QuorumCert) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_QuorumCert.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -583,13 +768,27 @@ func (m *QuorumCert) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_QuorumCert proto.InternalMessageInfo
 
-func (m *QuorumCert) GetCerts() map[uint64]*Certification {
+func (m *QuorumCert) GetCerts() []*CertEntry {
 	if m != nil {
 		return m.Certs
 	}
 	return nil
 }
 
+func (m *QuorumCert) GetSigners() []byte {
+	if m != nil {
+		return m.Signers
+	}
+	return nil
+}
+
+func (m *QuorumCert) GetAggSignature() []byte {
+	if m != nil {
+		return m.AggSignature
+	}
+	return nil
+}
+
 // PartialOrder is a verified log order generated by every node in phalanx cluster which is used to notify others its
 // partial log order. it could be generated when current node has received efficient votes from other participates.
 type PartialOrder struct {
@@ -605,7 +804,7 @@ func (m *PartialOrder) Reset()         { *m = PartialOrder{} }
 func (m *PartialOrder) String() string { return proto.CompactTextString(m) }
 func (*PartialOrder) ProtoMessage()    {}
 func (*PartialOrder) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4dc296cbfe5ffcd5, []int{8}
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{9}
 }
 func (m *PartialOrder) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -615,7 +814,7 @@ func (m *PartialOrder) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return xxx_messageInfo_PartialOrder.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -655,6 +854,144 @@ func (m *PartialOrder) GetOrderedTime() int64 {
 	return 0
 }
 
+// FetchPartial is used to request a partial order which has been committed by consensus
+// but has not been received by current node.
+type FetchPartial struct {
+	// Author indicates the identifier of the node who is fetching the partial order.
+	Author uint64 `protobuf:"varint,1,opt,name=Author,proto3" json:"Author,omitempty"`
+	// Generator indicates the identifier of the node who has generated the partial order.
+	Generator uint64 `protobuf:"varint,2,opt,name=Generator,proto3" json:"Generator,omitempty"`
+	// Sequence indicates the sequence number of the partial order.
+	Sequence uint64 `protobuf:"varint,3,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
+}
+
+func (m *FetchPartial) Reset()         { *m = FetchPartial{} }
+func (m *FetchPartial) String() string { return proto.CompactTextString(m) }
+func (*FetchPartial) ProtoMessage()    {}
+func (*FetchPartial) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{10}
+}
+func (m *FetchPartial) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FetchPartial) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FetchPartial.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FetchPartial) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchPartial.Merge(m, src)
+}
+func (m *FetchPartial) XXX_Size() int {
+	return m.Size()
+}
+func (m *FetchPartial) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchPartial.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchPartial proto.InternalMessageInfo
+
+func (m *FetchPartial) GetAuthor() uint64 {
+	if m != nil {
+		return m.Author
+	}
+	return 0
+}
+
+func (m *FetchPartial) GetGenerator() uint64 {
+	if m != nil {
+		return m.Generator
+	}
+	return 0
+}
+
+func (m *FetchPartial) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// FetchCommand is used to request a command which has been referred by the partial orders
+// but has not been received by current node.
+type FetchCommand struct {
+	// Author indicates the identifier of the node who is fetching the command.
+	Author uint64 `protobuf:"varint,1,opt,name=Author,proto3" json:"Author,omitempty"`
+	// Digest indicates the identifier of the command.
+	Digest string `protobuf:"bytes,2,opt,name=Digest,proto3" json:"Digest,omitempty"`
+	// Client and Sequence indicate the missing command which stalls a client, and they are used once the digest is blank.
+	Client   uint64 `protobuf:"varint,3,opt,name=Client,proto3" json:"Client,omitempty"`
+	Sequence uint64 `protobuf:"varint,4,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
+}
+
+func (m *FetchCommand) Reset()         { *m = FetchCommand{} }
+func (m *FetchCommand) String() string { return proto.CompactTextString(m) }
+func (*FetchCommand) ProtoMessage()    {}
+func (*FetchCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{11}
+}
+func (m *FetchCommand) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FetchCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FetchCommand.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FetchCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchCommand.Merge(m, src)
+}
+func (m *FetchCommand) XXX_Size() int {
+	return m.Size()
+}
+func (m *FetchCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchCommand proto.InternalMessageInfo
+
+func (m *FetchCommand) GetAuthor() uint64 {
+	if m != nil {
+		return m.Author
+	}
+	return 0
+}
+
+func (m *FetchCommand) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *FetchCommand) GetClient() uint64 {
+	if m != nil {
+		return m.Client
+	}
+	return 0
+}
+
+func (m *FetchCommand) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
 // PartialOrderBatch is used to collect the partial orders for bft consensus.
 type PartialOrderBatch struct {
 	// Author is the generator for current batch.
@@ -663,13 +1000,17 @@ type PartialOrderBatch struct {
 	HighOrders []*PartialOrder `protobuf:"bytes,2,rep,name=HighOrders,proto3" json:"HighOrders,omitempty"`
 	// SeqList indicates the sequence number for the high-order we have selected.
 	SeqList []uint64 `protobuf:"varint,3,rep,packed,name=SeqList,proto3" json:"SeqList,omitempty"`
+	// Epoch indicates the epoch of membership which the high-orders are collected for.
+	Epoch uint64 `protobuf:"varint,4,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	// Reconfiguration is the membership change which would take effect once current batch has been committed.
+	Reconfiguration *Reconfiguration `protobuf:"bytes,5,opt,name=Reconfiguration,proto3" json:"Reconfiguration,omitempty"`
 }
 
 func (m *PartialOrderBatch) Reset()         { *m = PartialOrderBatch{} }
 func (m *PartialOrderBatch) String() string { return proto.CompactTextString(m) }
 func (*PartialOrderBatch) ProtoMessage()    {}
 func (*PartialOrderBatch) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4dc296cbfe5ffcd5, []int{9}
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{12}
 }
 func (m *PartialOrderBatch) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -679,7 +1020,7 @@ func (m *PartialOrderBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, e
 		return xxx_messageInfo_PartialOrderBatch.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -719,783 +1060,3515 @@ func (m *PartialOrderBatch) GetSeqList() []uint64 {
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("protos.MessageType", MessageType_name, MessageType_value)
-	proto.RegisterType((*Transaction)(nil), "protos.Transaction")
-	proto.RegisterType((*Command)(nil), "protos.Command")
-	proto.RegisterType((*CommandProtoIndex)(nil), "protos.CommandProtoIndex")
-	proto.RegisterType((*ConsensusMessage)(nil), "protos.ConsensusMessage")
-	proto.RegisterType((*PreOrder)(nil), "protos.PreOrder")
-	proto.RegisterType((*Certification)(nil), "protos.Certification")
-	proto.RegisterType((*Vote)(nil), "protos.Vote")
-	proto.RegisterType((*QuorumCert)(nil), "protos.QuorumCert")
-	proto.RegisterMapType((map[uint64]*Certification)(nil), "protos.QuorumCert.CertsEntry")
-	proto.RegisterType((*PartialOrder)(nil), "protos.PartialOrder")
-	proto.RegisterType((*PartialOrderBatch)(nil), "protos.PartialOrderBatch")
+func (m *PartialOrderBatch) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
 }
 
-func init() { proto.RegisterFile("messages.proto", fileDescriptor_4dc296cbfe5ffcd5) }
+func (m *PartialOrderBatch) GetReconfiguration() *Reconfiguration {
+	if m != nil {
+		return m.Reconfiguration
+	}
+	return nil
+}
 
-var fileDescriptor_4dc296cbfe5ffcd5 = []byte{
-	// 692 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x4f, 0x6f, 0xd3, 0x30,
-	0x14, 0xaf, 0x93, 0xb4, 0x5b, 0x5f, 0xb6, 0xd1, 0x99, 0x81, 0xc2, 0x04, 0x55, 0x14, 0x21, 0x11,
-	0xf1, 0x67, 0x48, 0x1d, 0x12, 0x88, 0x9d, 0x58, 0xd7, 0x6d, 0x48, 0x4c, 0x6d, 0xbd, 0x6e, 0x12,
-	0xa7, 0xc9, 0xb4, 0xa6, 0x8d, 0x58, 0x9d, 0xcd, 0x76, 0x10, 0x15, 0x37, 0x3e, 0x01, 0xe2, 0x23,
-	0x71, 0xe2, 0xb8, 0x23, 0x47, 0xb4, 0x7d, 0x04, 0xbe, 0x00, 0x8a, 0x93, 0x34, 0xe9, 0xa6, 0x5e,
-	0x38, 0xc5, 0xef, 0xf9, 0x97, 0x9f, 0xdf, 0xef, 0xfd, 0x9e, 0x0d, 0x2b, 0x63, 0x26, 0x25, 0x1d,
-	0x32, 0xb9, 0x71, 0x26, 0x42, 0x15, 0xe2, 0x8a, 0xfe, 0x48, 0xef, 0x3d, 0xd8, 0x3d, 0x41, 0xb9,
-	0xa4, 0x7d, 0x15, 0x84, 0x1c, 0x63, 0xb0, 0xf6, 0xa9, 0x1c, 0x39, 0xc8, 0x45, 0x7e, 0x95, 0xe8,
-	0x35, 0x76, 0x60, 0xa1, 0x43, 0x27, 0xa7, 0x21, 0x1d, 0x38, 0x86, 0x8b, 0xfc, 0x25, 0x92, 0x85,
-	0xf8, 0x3e, 0x54, 0x7b, 0xc1, 0x98, 0x49, 0x45, 0xc7, 0x67, 0x8e, 0xe9, 0x22, 0xdf, 0x24, 0x79,
-	0xc2, 0xfb, 0x8b, 0x60, 0xa1, 0x19, 0x8e, 0xc7, 0x94, 0x0f, 0xf0, 0x5d, 0xa8, 0xbc, 0x89, 0xd4,
-	0x28, 0x14, 0x9a, 0xd9, 0x22, 0x69, 0x84, 0xd7, 0x61, 0xf1, 0x90, 0x9d, 0x47, 0x8c, 0xf7, 0x99,
-	0x26, 0xb7, 0xc8, 0x34, 0x8e, 0xff, 0xd9, 0x09, 0x86, 0x4c, 0x2a, 0x4d, 0x5d, 0x25, 0x69, 0x84,
-	0x9f, 0xc5, 0xb4, 0x5c, 0x31, 0xae, 0x1c, 0xcb, 0x35, 0x7d, 0xbb, 0x71, 0x3b, 0xd1, 0x24, 0x37,
-	0x0a, 0x4a, 0x48, 0x86, 0x89, 0x8f, 0x88, 0x65, 0xbc, 0x0b, 0xa4, 0x72, 0xca, 0xae, 0xe9, 0x57,
-	0xc9, 0x34, 0xc6, 0x6b, 0x50, 0xde, 0x8b, 0x0b, 0x76, 0x2a, 0xba, 0xf8, 0x24, 0xc0, 0x5b, 0x60,
-	0xef, 0x8a, 0x90, 0x2b, 0x12, 0x71, 0xce, 0x84, 0xb3, 0xe0, 0x22, 0xdf, 0x6e, 0xdc, 0xcb, 0x0e,
-	0x49, 0x25, 0x75, 0xe2, 0xe8, 0x2d, 0x1f, 0xb0, 0x2f, 0xa4, 0x88, 0xf6, 0xf6, 0x60, 0xf5, 0x06,
-	0xe2, 0x7f, 0xe4, 0x7b, 0x13, 0xa8, 0x35, 0x43, 0x2e, 0x19, 0x97, 0x91, 0x3c, 0x48, 0xcc, 0xc3,
-	0x8f, 0xc0, 0xea, 0x4d, 0xce, 0x98, 0x66, 0x59, 0xc9, 0x75, 0xa7, 0xdb, 0xf1, 0x16, 0xd1, 0x80,
-	0xd8, 0xc7, 0x5d, 0x11, 0x8e, 0x53, 0x52, 0xbd, 0xc6, 0x2b, 0x60, 0xf4, 0x42, 0xdd, 0x4b, 0x8b,
-	0x18, 0xbd, 0xb0, 0xe8, 0xab, 0x35, 0xe3, 0xab, 0xf7, 0x13, 0xc1, 0x62, 0x47, 0xb0, 0xb6, 0x18,
-	0x30, 0x51, 0xb0, 0x01, 0xcd, 0xd8, 0x90, 0x6b, 0x32, 0xe6, 0x6a, 0x32, 0xaf, 0x59, 0xea, 0x82,
-	0x9d, 0x36, 0x47, 0xdb, 0x61, 0x69, 0x3b, 0x8a, 0x29, 0xfc, 0x10, 0x96, 0xa7, 0x13, 0x34, 0xb5,
-	0xcc, 0x24, 0xb3, 0x49, 0xec, 0xc1, 0x52, 0x87, 0x0a, 0xc6, 0x55, 0x5a, 0x59, 0x45, 0x57, 0x36,
-	0x93, 0xf3, 0x9e, 0xc3, 0x72, 0x93, 0x09, 0x15, 0x7c, 0x0c, 0xfa, 0x54, 0xcf, 0x76, 0x1d, 0xe0,
-	0x30, 0x18, 0x72, 0xaa, 0x22, 0xc1, 0xa4, 0x83, 0x5c, 0xd3, 0x5f, 0x22, 0x85, 0x8c, 0x27, 0xc1,
-	0x3a, 0x0e, 0x15, 0x9b, 0x6b, 0x56, 0xde, 0x08, 0x63, 0xa6, 0x11, 0x5b, 0xd7, 0x0e, 0xd2, 0xaa,
-	0xed, 0xc6, 0x9d, 0xe9, 0xc0, 0x14, 0x37, 0xc9, 0x2c, 0xd6, 0xfb, 0x81, 0x00, 0xba, 0x51, 0x28,
-	0xa2, 0x71, 0x9c, 0xc7, 0x9b, 0x50, 0x8e, 0xbf, 0x49, 0x79, 0x76, 0xe3, 0x41, 0xc6, 0x91, 0x43,
-	0x34, 0x9d, 0x6c, 0x71, 0x25, 0x26, 0x24, 0xc1, 0xae, 0xb7, 0x01, 0xf2, 0x24, 0xae, 0x81, 0xf9,
-	0x89, 0x4d, 0xd2, 0xda, 0xe3, 0x25, 0x7e, 0x02, 0xe5, 0xcf, 0xf4, 0x34, 0x4a, 0x46, 0x6c, 0x6e,
-	0x61, 0x09, 0xe6, 0xb5, 0xf1, 0x0a, 0x79, 0xdf, 0x90, 0xee, 0xaf, 0x0a, 0xe8, 0x69, 0x32, 0x03,
-	0x4f, 0xf3, 0x79, 0xd0, 0xc4, 0x76, 0xa3, 0x96, 0x91, 0x64, 0x79, 0x92, 0x4f, 0x8c, 0x07, 0x46,
-	0xb7, 0x99, 0x1e, 0x86, 0x6f, 0x2a, 0x20, 0x46, 0xb7, 0x19, 0x4f, 0x82, 0x06, 0xb3, 0x81, 0xbe,
-	0x7f, 0xc9, 0xe3, 0x51, 0x4c, 0x79, 0x5f, 0x61, 0xb5, 0x58, 0xc3, 0x36, 0x55, 0xfd, 0xd1, 0x5c,
-	0x6f, 0x5e, 0x00, 0xec, 0x07, 0xc3, 0x91, 0x46, 0x4a, 0xc7, 0xd0, 0xcd, 0x5b, 0x9b, 0x96, 0x58,
-	0xa0, 0x21, 0x05, 0x5c, 0x7c, 0x03, 0x0e, 0xd9, 0xb9, 0x1e, 0x33, 0xd3, 0x35, 0x7d, 0x8b, 0x64,
-	0xe1, 0xe3, 0x97, 0x60, 0x17, 0x2e, 0x15, 0x5e, 0x86, 0x6a, 0x87, 0xb4, 0x4e, 0xda, 0x64, 0xa7,
-	0x45, 0x6a, 0x25, 0xbc, 0x08, 0xd6, 0x71, 0xbb, 0xd7, 0xaa, 0x21, 0x7c, 0x0b, 0xec, 0xee, 0x51,
-	0x9b, 0x1c, 0x1d, 0x9c, 0x34, 0x5b, 0xa4, 0x57, 0x33, 0xb6, 0x9d, 0x5f, 0x97, 0x75, 0x74, 0x71,
-	0x59, 0x47, 0x7f, 0x2e, 0xeb, 0xe8, 0xfb, 0x55, 0xbd, 0x74, 0x71, 0x55, 0x2f, 0xfd, 0xbe, 0xaa,
-	0x97, 0x3e, 0x24, 0x2f, 0xee, 0xe6, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x47, 0xc1, 0x8b, 0xbc,
-	0x8a, 0x05, 0x00, 0x00,
+// MisbehaviorProof contains the conflicting messages generated by offender.
+type MisbehaviorProof struct {
+	// Type indicates the kind of misbehavior.
+	Type MisbehaviorType `protobuf:"varint,1,opt,name=Type,proto3,enum=protos.MisbehaviorType" json:"Type,omitempty"`
+	// Offender indicates the identifier of the node which has generated the conflicting messages.
+	Offender uint64 `protobuf:"varint,2,opt,name=Offender,proto3" json:"Offender,omitempty"`
+	// Sequence indicates the sequence number of conflicting messages.
+	Sequence uint64 `protobuf:"varint,3,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
+	// FirstPreOrder and SecondPreOrder are the conflicting pre-orders for PRE_ORDER_EQUIVOCATION.
+	FirstPreOrder  *PreOrder `protobuf:"bytes,4,opt,name=FirstPreOrder,proto3" json:"FirstPreOrder,omitempty"`
+	SecondPreOrder *PreOrder `protobuf:"bytes,5,opt,name=SecondPreOrder,proto3" json:"SecondPreOrder,omitempty"`
+	// FirstPartial and SecondPartial are the conflicting partial orders for PARTIAL_ORDER_EQUIVOCATION.
+	FirstPartial  *PartialOrder `protobuf:"bytes,6,opt,name=FirstPartial,proto3" json:"FirstPartial,omitempty"`
+	SecondPartial *PartialOrder `protobuf:"bytes,7,opt,name=SecondPartial,proto3" json:"SecondPartial,omitempty"`
+	// FirstCommand and SecondCommand are the conflicting commands for CLIENT_EQUIVOCATION.
+	FirstCommand  *Command `protobuf:"bytes,8,opt,name=FirstCommand,proto3" json:"FirstCommand,omitempty"`
+	SecondCommand *Command `protobuf:"bytes,9,opt,name=SecondCommand,proto3" json:"SecondCommand,omitempty"`
 }
 
-func (m *Transaction) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *MisbehaviorProof) Reset()         { *m = MisbehaviorProof{} }
+func (m *MisbehaviorProof) String() string { return proto.CompactTextString(m) }
+func (*MisbehaviorProof) ProtoMessage()    {}
+func (*MisbehaviorProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{13}
+}
+func (m *MisbehaviorProof) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MisbehaviorProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MisbehaviorProof.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return dAtA[:n], nil
+}
+func (m *MisbehaviorProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MisbehaviorProof.Merge(m, src)
+}
+func (m *MisbehaviorProof) XXX_Size() int {
+	return m.Size()
+}
+func (m *MisbehaviorProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_MisbehaviorProof.DiscardUnknown(m)
 }
 
-func (m *Transaction) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Hash) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(len(m.Hash)))
-		i += copy(dAtA[i:], m.Hash)
-	}
-	if len(m.Payload) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(len(m.Payload)))
-		i += copy(dAtA[i:], m.Payload)
+var xxx_messageInfo_MisbehaviorProof proto.InternalMessageInfo
+
+func (m *MisbehaviorProof) GetType() MisbehaviorType {
+	if m != nil {
+		return m.Type
 	}
-	if m.Timestamp != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Timestamp))
+	return MisbehaviorType_PRE_ORDER_EQUIVOCATION
+}
+
+func (m *MisbehaviorProof) GetOffender() uint64 {
+	if m != nil {
+		return m.Offender
 	}
-	return i, nil
+	return 0
 }
 
-func (m *Command) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *MisbehaviorProof) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Command) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Author != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+func (m *MisbehaviorProof) GetFirstPreOrder() *PreOrder {
+	if m != nil {
+		return m.FirstPreOrder
 	}
-	if m.Sequence != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
-	}
-	if len(m.Digest) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(len(m.Digest)))
-		i += copy(dAtA[i:], m.Digest)
-	}
-	if len(m.Content) > 0 {
-		for _, msg := range m.Content {
-			dAtA[i] = 0x22
-			i++
-			i = encodeVarintMessages(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
-	}
-	if len(m.HashList) > 0 {
-		for _, s := range m.HashList {
-			dAtA[i] = 0x2a
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			dAtA[i] = uint8(l)
-			i++
-			i += copy(dAtA[i:], s)
-		}
-	}
-	if m.GTime != 0 {
-		dAtA[i] = 0x30
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.GTime))
-	}
-	if m.FrontRunner != nil {
-		dAtA[i] = 0x3a
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.FrontRunner.Size()))
-		n1, err := m.FrontRunner.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n1
-	}
-	return i, nil
+	return nil
 }
 
-func (m *CommandProtoIndex) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *MisbehaviorProof) GetSecondPreOrder() *PreOrder {
+	if m != nil {
+		return m.SecondPreOrder
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *CommandProtoIndex) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Author != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
-	}
-	if m.Sequence != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
+func (m *MisbehaviorProof) GetFirstPartial() *PartialOrder {
+	if m != nil {
+		return m.FirstPartial
 	}
-	return i, nil
+	return nil
 }
 
-func (m *ConsensusMessage) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *MisbehaviorProof) GetSecondPartial() *PartialOrder {
+	if m != nil {
+		return m.SecondPartial
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *ConsensusMessage) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Type != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Type))
-	}
-	if m.From != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.From))
-	}
-	if m.To != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.To))
-	}
-	if len(m.Payload) > 0 {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(len(m.Payload)))
-		i += copy(dAtA[i:], m.Payload)
+func (m *MisbehaviorProof) GetFirstCommand() *Command {
+	if m != nil {
+		return m.FirstCommand
 	}
-	return i, nil
+	return nil
 }
 
-func (m *PreOrder) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *MisbehaviorProof) GetSecondCommand() *Command {
+	if m != nil {
+		return m.SecondCommand
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *PreOrder) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Digest) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(len(m.Digest)))
-		i += copy(dAtA[i:], m.Digest)
-	}
-	if m.Author != 0 {
-		dAtA[i] = 0x10
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
-	}
-	if m.Sequence != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
-	}
-	if len(m.CommandList) > 0 {
-		for _, s := range m.CommandList {
-			dAtA[i] = 0x22
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			dAtA[i] = uint8(l)
-			i++
-			i += copy(dAtA[i:], s)
-		}
-	}
-	if len(m.TimestampList) > 0 {
-		dAtA3 := make([]byte, len(m.TimestampList)*10)
-		var j2 int
-		for _, num1 := range m.TimestampList {
-			num := uint64(num1)
-			for num >= 1<<7 {
-				dAtA3[j2] = uint8(uint64(num)&0x7f | 0x80)
-				num >>= 7
-				j2++
-			}
-			dAtA3[j2] = uint8(num)
-			j2++
+// ReplicaInfo is the information of a participant in phalanx cluster.
+type ReplicaInfo struct {
+	// ID is the identifier of the participant.
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// PublicKey is the encoded public key of the participant, nil means we could keep the one in previous epoch.
+	PublicKey []byte `protobuf:"bytes,2,opt,name=PublicKey,proto3" json:"PublicKey,omitempty"`
+	// Possession is the proof-of-possession generated with the private key of PublicKey, which is essential for the
+	// public keys whose signatures could be aggregated.
+	Possession *Certification `protobuf:"bytes,3,opt,name=Possession,proto3" json:"Possession,omitempty"`
+}
+
+func (m *ReplicaInfo) Reset()         { *m = ReplicaInfo{} }
+func (m *ReplicaInfo) String() string { return proto.CompactTextString(m) }
+func (*ReplicaInfo) ProtoMessage()    {}
+func (*ReplicaInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{14}
+}
+func (m *ReplicaInfo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReplicaInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReplicaInfo.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		dAtA[i] = 0x2a
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(j2))
-		i += copy(dAtA[i:], dAtA3[:j2])
-	}
-	if len(m.ParentDigest) > 0 {
-		dAtA[i] = 0x32
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(len(m.ParentDigest)))
-		i += copy(dAtA[i:], m.ParentDigest)
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *ReplicaInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicaInfo.Merge(m, src)
+}
+func (m *ReplicaInfo) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReplicaInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicaInfo.DiscardUnknown(m)
 }
 
-func (m *Certification) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ReplicaInfo proto.InternalMessageInfo
+
+func (m *ReplicaInfo) GetID() uint64 {
+	if m != nil {
+		return m.ID
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *Certification) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Signatures) > 0 {
-		for _, b := range m.Signatures {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintMessages(dAtA, i, uint64(len(b)))
-			i += copy(dAtA[i:], b)
-		}
+func (m *ReplicaInfo) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
 	}
-	return i, nil
+	return nil
 }
 
-func (m *Vote) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *ReplicaInfo) GetPossession() *Certification {
+	if m != nil {
+		return m.Possession
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *Vote) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Author != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
-	}
-	if len(m.Digest) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(len(m.Digest)))
-		i += copy(dAtA[i:], m.Digest)
-	}
-	if m.Certification != nil {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Certification.Size()))
-		n4, err := m.Certification.MarshalTo(dAtA[i:])
+// Reconfiguration is used to change the membership and public keys of phalanx cluster.
+type Reconfiguration struct {
+	// Epoch indicates the epoch number of the new membership.
+	Epoch uint64 `protobuf:"varint,1,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	// Replicas are the participants in the new membership.
+	Replicas []*ReplicaInfo `protobuf:"bytes,2,rep,name=Replicas,proto3" json:"Replicas,omitempty"`
+	// QC is the quorum-cert of the participants in current epoch, which authorizes the membership change.
+	QC *QuorumCert `protobuf:"bytes,3,opt,name=QC,proto3" json:"QC,omitempty"`
+}
+
+func (m *Reconfiguration) Reset()         { *m = Reconfiguration{} }
+func (m *Reconfiguration) String() string { return proto.CompactTextString(m) }
+func (*Reconfiguration) ProtoMessage()    {}
+func (*Reconfiguration) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{15}
+}
+func (m *Reconfiguration) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Reconfiguration) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Reconfiguration.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
-		i += n4
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *Reconfiguration) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Reconfiguration.Merge(m, src)
+}
+func (m *Reconfiguration) XXX_Size() int {
+	return m.Size()
+}
+func (m *Reconfiguration) XXX_DiscardUnknown() {
+	xxx_messageInfo_Reconfiguration.DiscardUnknown(m)
 }
 
-func (m *QuorumCert) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_Reconfiguration proto.InternalMessageInfo
+
+func (m *Reconfiguration) GetEpoch() uint64 {
+	if m != nil {
+		return m.Epoch
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *QuorumCert) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Certs) > 0 {
-		for k, _ := range m.Certs {
-			dAtA[i] = 0xa
-			i++
-			v := m.Certs[k]
-			msgSize := 0
-			if v != nil {
-				msgSize = v.Size()
-				msgSize += 1 + sovMessages(uint64(msgSize))
-			}
-			mapSize := 1 + sovMessages(uint64(k)) + msgSize
-			i = encodeVarintMessages(dAtA, i, uint64(mapSize))
-			dAtA[i] = 0x8
-			i++
-			i = encodeVarintMessages(dAtA, i, uint64(k))
-			if v != nil {
-				dAtA[i] = 0x12
-				i++
-				i = encodeVarintMessages(dAtA, i, uint64(v.Size()))
-				n5, err := v.MarshalTo(dAtA[i:])
-				if err != nil {
-					return 0, err
-				}
-				i += n5
-			}
-		}
+func (m *Reconfiguration) GetReplicas() []*ReplicaInfo {
+	if m != nil {
+		return m.Replicas
 	}
-	return i, nil
+	return nil
 }
 
-func (m *PartialOrder) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *Reconfiguration) GetQC() *QuorumCert {
+	if m != nil {
+		return m.QC
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *PartialOrder) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.PreOrder != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.PreOrder.Size()))
-		n6, err := m.PreOrder.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n6
-	}
-	if m.QC != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.QC.Size()))
-		n7, err := m.QC.MarshalTo(dAtA[i:])
+// WALSnapshot is the status of meta pool at a stable checkpoint, which replaces the entries persisted before it.
+type WALSnapshot struct {
+	// HighOrder is the highest pre-order we have generated.
+	HighOrder *PreOrder `protobuf:"bytes,1,opt,name=HighOrder,proto3" json:"HighOrder,omitempty"`
+	// Pending are the pre-orders we have generated which are still waiting for quorum votes.
+	Pending []*PreOrder `protobuf:"bytes,2,rep,name=Pending,proto3" json:"Pending,omitempty"`
+	// CommitNo is the committed sequence number for each participant.
+	CommitNo []uint64 `protobuf:"varint,3,rep,packed,name=CommitNo,proto3" json:"CommitNo,omitempty"`
+}
+
+func (m *WALSnapshot) Reset()         { *m = WALSnapshot{} }
+func (m *WALSnapshot) String() string { return proto.CompactTextString(m) }
+func (*WALSnapshot) ProtoMessage()    {}
+func (*WALSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{16}
+}
+func (m *WALSnapshot) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WALSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WALSnapshot.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
-		i += n7
-	}
-	if m.OrderedTime != 0 {
-		dAtA[i] = 0x18
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.OrderedTime))
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *WALSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WALSnapshot.Merge(m, src)
+}
+func (m *WALSnapshot) XXX_Size() int {
+	return m.Size()
+}
+func (m *WALSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_WALSnapshot.DiscardUnknown(m)
 }
 
-func (m *PartialOrderBatch) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_WALSnapshot proto.InternalMessageInfo
+
+func (m *WALSnapshot) GetHighOrder() *PreOrder {
+	if m != nil {
+		return m.HighOrder
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *PartialOrderBatch) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Author != 0 {
-		dAtA[i] = 0x8
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+func (m *WALSnapshot) GetPending() []*PreOrder {
+	if m != nil {
+		return m.Pending
 	}
-	if len(m.HighOrders) > 0 {
-		for _, msg := range m.HighOrders {
-			dAtA[i] = 0x12
-			i++
-			i = encodeVarintMessages(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
+	return nil
+}
+
+func (m *WALSnapshot) GetCommitNo() []uint64 {
+	if m != nil {
+		return m.CommitNo
 	}
-	if len(m.SeqList) > 0 {
-		dAtA9 := make([]byte, len(m.SeqList)*10)
-		var j8 int
-		for _, num := range m.SeqList {
-			for num >= 1<<7 {
-				dAtA9[j8] = uint8(uint64(num)&0x7f | 0x80)
-				num >>= 7
-				j8++
-			}
-			dAtA9[j8] = uint8(num)
-			j8++
+	return nil
+}
+
+// WALEntry is the record persisted by meta pool, which is used to recover the status of meta pool after a restart.
+type WALEntry struct {
+	// Type indicates the entry type which could be used in replay process.
+	Type WALEntryType `protobuf:"varint,1,opt,name=Type,proto3,enum=protos.WALEntryType" json:"Type,omitempty"`
+	// PreOrder is the pre-order we have generated, or the pre-order we have voted for.
+	PreOrder *PreOrder `protobuf:"bytes,2,opt,name=PreOrder,proto3" json:"PreOrder,omitempty"`
+	// Partial is the partial order we have verified and recorded.
+	Partial *PartialOrder `protobuf:"bytes,3,opt,name=Partial,proto3" json:"Partial,omitempty"`
+	// CommitNo is the committed sequence number for each participant after a query stream has been committed.
+	CommitNo []uint64 `protobuf:"varint,4,rep,packed,name=CommitNo,proto3" json:"CommitNo,omitempty"`
+	// Reconfiguration is the membership change we have committed.
+	Reconfiguration *Reconfiguration `protobuf:"bytes,5,opt,name=Reconfiguration,proto3" json:"Reconfiguration,omitempty"`
+	// Snapshot is the status of meta pool we have compacted the write-ahead log with.
+	Snapshot *WALSnapshot `protobuf:"bytes,6,opt,name=Snapshot,proto3" json:"Snapshot,omitempty"`
+}
+
+func (m *WALEntry) Reset()         { *m = WALEntry{} }
+func (m *WALEntry) String() string { return proto.CompactTextString(m) }
+func (*WALEntry) ProtoMessage()    {}
+func (*WALEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4dc296cbfe5ffcd5, []int{17}
+}
+func (m *WALEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WALEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WALEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintMessages(dAtA, i, uint64(j8))
-		i += copy(dAtA[i:], dAtA9[:j8])
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *WALEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WALEntry.Merge(m, src)
+}
+func (m *WALEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *WALEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_WALEntry.DiscardUnknown(m)
 }
 
-func encodeVarintMessages(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+var xxx_messageInfo_WALEntry proto.InternalMessageInfo
+
+func (m *WALEntry) GetType() WALEntryType {
+	if m != nil {
+		return m.Type
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return WALEntryType_WAL_PRE_ORDER
 }
-func (m *Transaction) Size() (n int) {
-	if m == nil {
-		return 0
+
+func (m *WALEntry) GetPreOrder() *PreOrder {
+	if m != nil {
+		return m.PreOrder
 	}
-	var l int
-	_ = l
-	l = len(m.Hash)
-	if l > 0 {
-		n += 1 + l + sovMessages(uint64(l))
+	return nil
+}
+
+func (m *WALEntry) GetPartial() *PartialOrder {
+	if m != nil {
+		return m.Partial
 	}
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovMessages(uint64(l))
+	return nil
+}
+
+func (m *WALEntry) GetCommitNo() []uint64 {
+	if m != nil {
+		return m.CommitNo
 	}
-	if m.Timestamp != 0 {
-		n += 1 + sovMessages(uint64(m.Timestamp))
+	return nil
+}
+
+func (m *WALEntry) GetReconfiguration() *Reconfiguration {
+	if m != nil {
+		return m.Reconfiguration
 	}
-	return n
+	return nil
 }
 
-func (m *Command) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *WALEntry) GetSnapshot() *WALSnapshot {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("protos.MessageType", MessageType_name, MessageType_value)
+	proto.RegisterEnum("protos.MisbehaviorType", MisbehaviorType_name, MisbehaviorType_value)
+	proto.RegisterEnum("protos.WALEntryType", WALEntryType_name, WALEntryType_value)
+	proto.RegisterType((*Transaction)(nil), "protos.Transaction")
+	proto.RegisterType((*Command)(nil), "protos.Command")
+	proto.RegisterType((*CommandProtoIndex)(nil), "protos.CommandProtoIndex")
+	proto.RegisterType((*ConsensusMessage)(nil), "protos.ConsensusMessage")
+	proto.RegisterType((*PreOrder)(nil), "protos.PreOrder")
+	proto.RegisterType((*Certification)(nil), "protos.Certification")
+	proto.RegisterType((*Vote)(nil), "protos.Vote")
+	proto.RegisterType((*CertEntry)(nil), "protos.CertEntry")
+	proto.RegisterType((*QuorumCert)(nil), "protos.QuorumCert")
+	proto.RegisterType((*PartialOrder)(nil), "protos.PartialOrder")
+	proto.RegisterType((*FetchPartial)(nil), "protos.FetchPartial")
+	proto.RegisterType((*FetchCommand)(nil), "protos.FetchCommand")
+	proto.RegisterType((*PartialOrderBatch)(nil), "protos.PartialOrderBatch")
+	proto.RegisterType((*MisbehaviorProof)(nil), "protos.MisbehaviorProof")
+	proto.RegisterType((*ReplicaInfo)(nil), "protos.ReplicaInfo")
+	proto.RegisterType((*Reconfiguration)(nil), "protos.Reconfiguration")
+	proto.RegisterType((*WALSnapshot)(nil), "protos.WALSnapshot")
+	proto.RegisterType((*WALEntry)(nil), "protos.WALEntry")
+}
+
+func init() { proto.RegisterFile("messages.proto", fileDescriptor_4dc296cbfe5ffcd5) }
+
+var fileDescriptor_4dc296cbfe5ffcd5 = []byte{
+	// 1305 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xcb, 0x6e, 0xdb, 0x46,
+	0x17, 0x36, 0x2f, 0xba, 0x1d, 0xc9, 0x36, 0x3d, 0xce, 0x9f, 0xf0, 0x37, 0x02, 0x41, 0x20, 0x0a,
+	0x44, 0x75, 0x5b, 0x07, 0x70, 0x9a, 0x22, 0x68, 0x56, 0xb4, 0x4c, 0xd9, 0x42, 0x6d, 0x49, 0x1e,
+	0xd1, 0x0e, 0xba, 0x52, 0x19, 0x69, 0x24, 0x11, 0xb0, 0x48, 0x87, 0x43, 0x15, 0x35, 0xd0, 0x55,
+	0x81, 0xee, 0xdb, 0x4d, 0x1f, 0xa0, 0xab, 0xbe, 0x44, 0xf7, 0x05, 0xba, 0xc9, 0xa2, 0x8b, 0x2e,
+	0x8b, 0xe4, 0x45, 0x8a, 0x19, 0x0e, 0x6f, 0x72, 0xa4, 0x14, 0x45, 0x57, 0xd2, 0x39, 0xf3, 0xcd,
+	0xb9, 0x7d, 0xdf, 0x1c, 0xd9, 0xb0, 0x35, 0x27, 0x94, 0x3a, 0x53, 0x42, 0x0f, 0x6e, 0x02, 0x3f,
+	0xf4, 0x51, 0x91, 0x7f, 0x50, 0xe3, 0x4b, 0xa8, 0xda, 0x81, 0xe3, 0x51, 0x67, 0x14, 0xba, 0xbe,
+	0x87, 0x10, 0xa8, 0xa7, 0x0e, 0x9d, 0xe9, 0x52, 0x43, 0x6a, 0x56, 0x30, 0xff, 0x8e, 0x74, 0x28,
+	0xf5, 0x9d, 0xdb, 0x6b, 0xdf, 0x19, 0xeb, 0x72, 0x43, 0x6a, 0xd6, 0x70, 0x6c, 0xa2, 0x87, 0x50,
+	0xb1, 0xdd, 0x39, 0xa1, 0xa1, 0x33, 0xbf, 0xd1, 0x95, 0x86, 0xd4, 0x54, 0x70, 0xea, 0x30, 0x7e,
+	0x91, 0xa1, 0xd4, 0xf2, 0xe7, 0x73, 0xc7, 0x1b, 0xa3, 0xfb, 0x50, 0x34, 0x17, 0xe1, 0xcc, 0x0f,
+	0x78, 0x64, 0x15, 0x0b, 0x0b, 0xed, 0x41, 0x79, 0x40, 0x5e, 0x2d, 0x88, 0x37, 0x22, 0x3c, 0xb8,
+	0x8a, 0x13, 0x9b, 0xdd, 0x39, 0x76, 0xa7, 0x84, 0x86, 0x3c, 0x74, 0x05, 0x0b, 0x0b, 0x7d, 0xc2,
+	0xc2, 0x7a, 0x21, 0xf1, 0x42, 0x5d, 0x6d, 0x28, 0xcd, 0xea, 0xe1, 0x6e, 0xd4, 0x13, 0x3d, 0xc8,
+	0x74, 0x82, 0x63, 0x0c, 0x4b, 0xc1, 0xda, 0x38, 0x73, 0x69, 0xa8, 0x17, 0x1a, 0x4a, 0xb3, 0x82,
+	0x13, 0x1b, 0xdd, 0x83, 0xc2, 0x09, 0x2b, 0x58, 0x2f, 0xf2, 0xe2, 0x23, 0x03, 0x3d, 0x87, 0x6a,
+	0x3b, 0xf0, 0xbd, 0x10, 0x2f, 0x3c, 0x8f, 0x04, 0x7a, 0xa9, 0x21, 0x35, 0xab, 0x87, 0xff, 0x8f,
+	0x93, 0x88, 0x96, 0xfa, 0xcc, 0xea, 0x78, 0x63, 0xf2, 0x0d, 0xce, 0xa2, 0xd1, 0x13, 0xa8, 0x0c,
+	0xdc, 0xa9, 0xe7, 0x84, 0x8b, 0x80, 0xe8, 0x65, 0x7e, 0xf5, 0x7f, 0xc9, 0x55, 0x12, 0x84, 0xee,
+	0xc4, 0x1d, 0x39, 0xbc, 0xc2, 0x14, 0x67, 0x9c, 0xc0, 0xce, 0x9d, 0xb0, 0xff, 0x66, 0x66, 0xc6,
+	0x2d, 0x68, 0x2d, 0xdf, 0xa3, 0xc4, 0xa3, 0x0b, 0x7a, 0x1e, 0x31, 0x8e, 0x1e, 0x81, 0x6a, 0xdf,
+	0xde, 0x10, 0x1e, 0x65, 0x2b, 0x1d, 0x96, 0x38, 0x66, 0x47, 0x98, 0x03, 0x18, 0xf9, 0xed, 0xc0,
+	0x9f, 0x8b, 0xa0, 0xfc, 0x3b, 0xda, 0x02, 0xd9, 0xf6, 0x39, 0x01, 0x2a, 0x96, 0x6d, 0x3f, 0x2b,
+	0x06, 0x35, 0x27, 0x06, 0xe3, 0x57, 0x19, 0xca, 0xfd, 0x80, 0xf4, 0x82, 0x31, 0x09, 0x32, 0xdc,
+	0x49, 0x39, 0xee, 0xd2, 0x9e, 0xe4, 0x95, 0x3d, 0x29, 0x4b, 0x3a, 0x68, 0x40, 0x55, 0x0c, 0x87,
+	0x73, 0xa8, 0x72, 0x0e, 0xb3, 0x2e, 0xf4, 0x01, 0x6c, 0x26, 0xb2, 0x4b, 0x78, 0x56, 0x70, 0xde,
+	0x89, 0x0c, 0xa8, 0xf5, 0x9d, 0x80, 0x78, 0xa1, 0xa8, 0xac, 0xc8, 0x2b, 0xcb, 0xf9, 0xf2, 0xec,
+	0x95, 0xfe, 0x19, 0x7b, 0xa8, 0x0e, 0xd0, 0xba, 0x76, 0x89, 0x17, 0xf2, 0xdc, 0xe5, 0x86, 0xd2,
+	0x54, 0x71, 0xc6, 0xc3, 0xca, 0x8b, 0xac, 0x01, 0x79, 0xc5, 0x21, 0x15, 0x0e, 0xc9, 0x3b, 0x8d,
+	0xc7, 0xb0, 0x99, 0xcb, 0xc0, 0xc2, 0x26, 0x39, 0xa8, 0x2e, 0x35, 0x94, 0x66, 0x0d, 0x67, 0x3c,
+	0x06, 0x05, 0xf5, 0xca, 0x0f, 0xc9, 0x4a, 0x9d, 0xa4, 0x1c, 0xc8, 0x39, 0x0e, 0x9e, 0x2f, 0x25,
+	0xd2, 0x95, 0x75, 0x7d, 0xe6, 0xb1, 0x46, 0x1b, 0x2a, 0xcc, 0x61, 0x79, 0x61, 0x70, 0xcb, 0xc4,
+	0xd1, 0x39, 0x16, 0x59, 0xe5, 0xce, 0x31, 0xfa, 0x10, 0x54, 0x76, 0xa8, 0xcb, 0xeb, 0x02, 0x72,
+	0x88, 0x41, 0x01, 0x2e, 0x16, 0x7e, 0xb0, 0x98, 0x33, 0x0b, 0x3d, 0x82, 0x02, 0xfb, 0x8c, 0xba,
+	0xac, 0x1e, 0xee, 0x64, 0x6f, 0xf2, 0x54, 0x38, 0x3a, 0x67, 0xf2, 0x63, 0x13, 0x20, 0x01, 0x8d,
+	0x77, 0x91, 0x30, 0x19, 0xbb, 0xe6, 0x74, 0x9a, 0x92, 0xa7, 0xf0, 0xe3, 0x9c, 0xcf, 0xf8, 0x4e,
+	0xe2, 0x12, 0x08, 0x5d, 0xe7, 0x3a, 0x92, 0xe9, 0xc7, 0xa9, 0x64, 0x79, 0x1b, 0xd5, 0x43, 0x2d,
+	0x4e, 0x1d, 0xfb, 0x71, 0x2a, 0x6a, 0x03, 0xe4, 0x8b, 0x96, 0x68, 0x0e, 0xc5, 0xb8, 0xb4, 0x0b,
+	0x2c, 0x5f, 0xb4, 0x98, 0x58, 0x39, 0x98, 0x8c, 0xf9, 0x5e, 0x89, 0x96, 0x62, 0xd6, 0x65, 0x7c,
+	0x05, 0xb5, 0x36, 0x09, 0x47, 0x33, 0x51, 0xc8, 0x4a, 0xfa, 0x1e, 0x42, 0xe5, 0x84, 0x78, 0x24,
+	0x70, 0xc2, 0xe4, 0xb5, 0xa4, 0x8e, 0x75, 0x0f, 0xc6, 0x08, 0x44, 0x86, 0xf7, 0x2d, 0xdf, 0x55,
+	0x02, 0xb9, 0x0f, 0xc5, 0x48, 0x9a, 0x22, 0xb2, 0xb0, 0x72, 0x39, 0xd5, 0xa5, 0x9c, 0x7f, 0x48,
+	0xb0, 0x93, 0x1d, 0xed, 0x91, 0x13, 0x8e, 0x66, 0x2b, 0x33, 0x7f, 0x0a, 0x70, 0xea, 0x4e, 0x67,
+	0x1c, 0xc9, 0x98, 0x64, 0xa4, 0xdf, 0x4b, 0x26, 0x9f, 0x09, 0x83, 0x33, 0x38, 0x4e, 0xbe, 0x78,
+	0x41, 0x0a, 0x7f, 0x41, 0xb1, 0xc9, 0xf6, 0xb8, 0x75, 0xe3, 0x8f, 0x66, 0xa2, 0xac, 0xc8, 0x40,
+	0x26, 0x6c, 0x63, 0x32, 0xf2, 0xbd, 0x89, 0x3b, 0x5d, 0x04, 0x91, 0xd4, 0x0b, 0x9c, 0xbc, 0x07,
+	0x71, 0xaa, 0xa5, 0x63, 0xbc, 0x8c, 0x37, 0x7e, 0x57, 0x40, 0x3b, 0x77, 0xe9, 0x4b, 0x32, 0x73,
+	0xbe, 0x76, 0xfd, 0xa0, 0x1f, 0xf8, 0xfe, 0x04, 0x7d, 0x94, 0x5b, 0xa8, 0x49, 0xb0, 0x0c, 0x2e,
+	0xb3, 0x54, 0xf7, 0xa0, 0xdc, 0x9b, 0x4c, 0x88, 0xc7, 0x24, 0x26, 0xb6, 0x75, 0x6c, 0xaf, 0xdd,
+	0x7a, 0x9f, 0xc1, 0x66, 0xdb, 0x0d, 0x68, 0x98, 0xe8, 0x53, 0x5d, 0xa1, 0xcf, 0x3c, 0x0c, 0x3d,
+	0x83, 0xad, 0x01, 0x6b, 0x62, 0x9c, 0x5c, 0x2c, 0xac, 0xb8, 0xb8, 0x84, 0x43, 0xcf, 0xa0, 0x16,
+	0x85, 0x8a, 0xe6, 0xcf, 0xf7, 0xe3, 0x2a, 0x5a, 0x72, 0x48, 0xf4, 0x39, 0x6c, 0x8a, 0x58, 0xe2,
+	0x6a, 0x69, 0xcd, 0xd5, 0x3c, 0x14, 0x3d, 0x11, 0x59, 0x85, 0x58, 0xc5, 0x4f, 0xe6, 0xf6, 0xd2,
+	0xaf, 0x2d, 0xce, 0x81, 0xd0, 0xd3, 0x38, 0x61, 0x7c, 0xab, 0xf2, 0xee, 0x5b, 0x79, 0x94, 0x11,
+	0x40, 0x15, 0x93, 0x9b, 0x6b, 0x77, 0xe4, 0x74, 0xbc, 0x89, 0x7f, 0x67, 0x7d, 0x3d, 0x84, 0x4a,
+	0x7f, 0xf1, 0xf2, 0xda, 0x1d, 0x7d, 0x41, 0x6e, 0xc5, 0x7a, 0x49, 0x1d, 0xe8, 0x29, 0x40, 0xdf,
+	0xa7, 0x94, 0x50, 0xfa, 0xde, 0x9d, 0x99, 0x01, 0x1a, 0xdf, 0xde, 0x11, 0x61, 0xaa, 0x56, 0x29,
+	0xab, 0xd6, 0xc7, 0x50, 0x16, 0xc5, 0xc5, 0x2f, 0x62, 0x37, 0x95, 0x69, 0x52, 0x34, 0x4e, 0x40,
+	0x62, 0x1d, 0x29, 0xeb, 0xd6, 0x91, 0xf1, 0xbd, 0x04, 0xd5, 0x17, 0xe6, 0xd9, 0xc0, 0x73, 0x6e,
+	0xe8, 0xcc, 0x0f, 0xd1, 0x01, 0x54, 0x92, 0x07, 0xb5, 0x72, 0xe3, 0xa5, 0x10, 0xb4, 0x0f, 0xa5,
+	0x3e, 0xf1, 0xc6, 0xae, 0x37, 0x15, 0x35, 0xdd, 0x45, 0xc7, 0x00, 0xa6, 0x66, 0x36, 0x68, 0x37,
+	0xec, 0xfa, 0xe2, 0x7d, 0x26, 0xb6, 0xf1, 0xb3, 0x0c, 0xe5, 0x17, 0xe6, 0x59, 0xf4, 0xb3, 0xd1,
+	0xcc, 0xbd, 0x9f, 0x44, 0x25, 0xf1, 0x79, 0xe6, 0xf1, 0x64, 0xf7, 0xb3, 0xfc, 0xde, 0xfd, 0x7c,
+	0x00, 0x25, 0xa1, 0x2a, 0x31, 0x95, 0x77, 0x0b, 0x30, 0x06, 0xe5, 0x0a, 0x56, 0xf3, 0x05, 0xff,
+	0x07, 0xbb, 0x83, 0x11, 0x1a, 0xcf, 0x5d, 0xbc, 0xa5, 0xdd, 0x4c, 0xab, 0xf1, 0x11, 0x4e, 0x40,
+	0xfb, 0x3f, 0x49, 0x50, 0xcd, 0xfc, 0x55, 0x86, 0x36, 0xa1, 0xd2, 0xc7, 0xd6, 0xb0, 0x87, 0x8f,
+	0x2d, 0xac, 0x6d, 0xa0, 0x32, 0xa8, 0x57, 0x3d, 0xdb, 0xd2, 0x24, 0xb4, 0x0d, 0xd5, 0x8b, 0xcb,
+	0x1e, 0xbe, 0x3c, 0x1f, 0xb6, 0x2c, 0x6c, 0x6b, 0x32, 0xda, 0x81, 0xcd, 0xb6, 0x65, 0xb7, 0x4e,
+	0x87, 0x7d, 0x13, 0xdb, 0x1d, 0xf3, 0x4c, 0x53, 0x10, 0x82, 0x2d, 0x6c, 0xd9, 0x97, 0xb8, 0x9b,
+	0xf8, 0xd4, 0x14, 0xd6, 0xea, 0x9d, 0x9f, 0x9b, 0xdd, 0x63, 0xad, 0x90, 0x81, 0xc5, 0xbe, 0x22,
+	0x0b, 0x7f, 0xde, 0x19, 0x1c, 0x59, 0xa7, 0xe6, 0x55, 0xa7, 0x87, 0xb5, 0xd2, 0xfe, 0x04, 0xb6,
+	0x97, 0x96, 0x1b, 0xda, 0x83, 0xfb, 0x49, 0x6d, 0x43, 0xeb, 0xe2, 0xb2, 0x73, 0xd5, 0x6b, 0x99,
+	0x76, 0xa7, 0xd7, 0xd5, 0x36, 0x50, 0x1d, 0xf6, 0x44, 0xce, 0x77, 0x9d, 0x4b, 0xe8, 0x01, 0xec,
+	0xb6, 0xce, 0x3a, 0x56, 0xd7, 0xce, 0x1f, 0xc8, 0xfb, 0x3f, 0x4a, 0x50, 0xcb, 0xaa, 0x80, 0x15,
+	0xfc, 0xc2, 0x3c, 0x1b, 0x66, 0xa7, 0x50, 0xe3, 0x42, 0x1a, 0xa6, 0x93, 0xe0, 0x00, 0xd1, 0xa2,
+	0x8c, 0xb6, 0x00, 0x98, 0x83, 0x35, 0xd3, 0xb1, 0x35, 0x85, 0xe5, 0x62, 0x36, 0xb6, 0x5a, 0xbd,
+	0x6e, 0xbb, 0x73, 0x72, 0x89, 0xa3, 0x5c, 0x2a, 0xd2, 0x78, 0xaa, 0xe1, 0xa0, 0x6b, 0xf6, 0x07,
+	0xa7, 0x3d, 0x5b, 0x2b, 0xa0, 0x7b, 0xa0, 0x31, 0xcf, 0x91, 0x39, 0xb0, 0x92, 0x80, 0xc5, 0x23,
+	0xfd, 0xb7, 0x37, 0x75, 0xe9, 0xf5, 0x9b, 0xba, 0xf4, 0xd7, 0x9b, 0xba, 0xf4, 0xc3, 0xdb, 0xfa,
+	0xc6, 0xeb, 0xb7, 0xf5, 0x8d, 0x3f, 0xdf, 0xd6, 0x37, 0x5e, 0x46, 0xff, 0x42, 0x3d, 0xf9, 0x7b,
+	0x00, 0x0a, 0x38, 0x6a, 0x45, 0x5b, 0x0d, 0x00, 0x00,
+}
+
+func (m *Transaction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Transaction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Transaction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Author != 0 {
-		n += 1 + sovMessages(uint64(m.Author))
+	if m.Timestamp != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.Sequence != 0 {
-		n += 1 + sovMessages(uint64(m.Sequence))
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0x12
 	}
-	l = len(m.Digest)
-	if l > 0 {
-		n += 1 + l + sovMessages(uint64(l))
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0xa
 	}
-	if len(m.Content) > 0 {
-		for _, e := range m.Content {
-			l = e.Size()
-			n += 1 + l + sovMessages(uint64(l))
+	return len(dAtA) - i, nil
+}
+
+func (m *Command) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Command) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Command) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Signature != nil {
+		{
+			size, err := m.Signature.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x42
 	}
-	if len(m.HashList) > 0 {
-		for _, s := range m.HashList {
-			l = len(s)
-			n += 1 + l + sovMessages(uint64(l))
+	if m.FrontRunner != nil {
+		{
+			size, err := m.FrontRunner.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x3a
 	}
 	if m.GTime != 0 {
-		n += 1 + sovMessages(uint64(m.GTime))
+		i = encodeVarintMessages(dAtA, i, uint64(m.GTime))
+		i--
+		dAtA[i] = 0x30
 	}
-	if m.FrontRunner != nil {
-		l = m.FrontRunner.Size()
-		n += 1 + l + sovMessages(uint64(l))
+	if len(m.HashList) > 0 {
+		for iNdEx := len(m.HashList) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.HashList[iNdEx])
+			copy(dAtA[i:], m.HashList[iNdEx])
+			i = encodeVarintMessages(dAtA, i, uint64(len(m.HashList[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
 	}
-	return n
-}
-
-func (m *CommandProtoIndex) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(m.Content) > 0 {
+		for iNdEx := len(m.Content) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Content[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMessages(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
 	}
-	var l int
-	_ = l
-	if m.Author != 0 {
-		n += 1 + sovMessages(uint64(m.Author))
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0x1a
 	}
 	if m.Sequence != 0 {
-		n += 1 + sovMessages(uint64(m.Sequence))
+		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	if m.Author != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *ConsensusMessage) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *CommandProtoIndex) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *CommandProtoIndex) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CommandProtoIndex) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Type != 0 {
-		n += 1 + sovMessages(uint64(m.Type))
-	}
-	if m.From != 0 {
-		n += 1 + sovMessages(uint64(m.From))
-	}
-	if m.To != 0 {
-		n += 1 + sovMessages(uint64(m.To))
+	if m.Sequence != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x10
 	}
-	l = len(m.Payload)
-	if l > 0 {
-		n += 1 + l + sovMessages(uint64(l))
+	if m.Author != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *PreOrder) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ConsensusMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ConsensusMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ConsensusMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Digest)
-	if l > 0 {
-		n += 1 + l + sovMessages(uint64(l))
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0x22
 	}
-	if m.Author != 0 {
-		n += 1 + sovMessages(uint64(m.Author))
+	if m.To != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.To))
+		i--
+		dAtA[i] = 0x18
 	}
-	if m.Sequence != 0 {
-		n += 1 + sovMessages(uint64(m.Sequence))
+	if m.From != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.From))
+		i--
+		dAtA[i] = 0x10
 	}
-	if len(m.CommandList) > 0 {
-		for _, s := range m.CommandList {
-			l = len(s)
-			n += 1 + l + sovMessages(uint64(l))
+	if m.Type != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PreOrder) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PreOrder) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PreOrder) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ClientSeqList) > 0 {
+		dAtA4 := make([]byte, len(m.ClientSeqList)*10)
+		var j3 int
+		for _, num := range m.ClientSeqList {
+			for num >= 1<<7 {
+				dAtA4[j3] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j3++
+			}
+			dAtA4[j3] = uint8(num)
+			j3++
+		}
+		i -= j3
+		copy(dAtA[i:], dAtA4[:j3])
+		i = encodeVarintMessages(dAtA, i, uint64(j3))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.ClientList) > 0 {
+		dAtA6 := make([]byte, len(m.ClientList)*10)
+		var j5 int
+		for _, num := range m.ClientList {
+			for num >= 1<<7 {
+				dAtA6[j5] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j5++
+			}
+			dAtA6[j5] = uint8(num)
+			j5++
 		}
+		i -= j5
+		copy(dAtA[i:], dAtA6[:j5])
+		i = encodeVarintMessages(dAtA, i, uint64(j5))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.Signature != nil {
+		{
+			size, err := m.Signature.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.ParentDigest) > 0 {
+		i -= len(m.ParentDigest)
+		copy(dAtA[i:], m.ParentDigest)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.ParentDigest)))
+		i--
+		dAtA[i] = 0x32
 	}
 	if len(m.TimestampList) > 0 {
-		l = 0
-		for _, e := range m.TimestampList {
-			l += sovMessages(uint64(e))
+		dAtA9 := make([]byte, len(m.TimestampList)*10)
+		var j8 int
+		for _, num1 := range m.TimestampList {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA9[j8] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j8++
+			}
+			dAtA9[j8] = uint8(num)
+			j8++
 		}
-		n += 1 + sovMessages(uint64(l)) + l
+		i -= j8
+		copy(dAtA[i:], dAtA9[:j8])
+		i = encodeVarintMessages(dAtA, i, uint64(j8))
+		i--
+		dAtA[i] = 0x2a
 	}
-	l = len(m.ParentDigest)
-	if l > 0 {
-		n += 1 + l + sovMessages(uint64(l))
+	if len(m.CommandList) > 0 {
+		for iNdEx := len(m.CommandList) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.CommandList[iNdEx])
+			copy(dAtA[i:], m.CommandList[iNdEx])
+			i = encodeVarintMessages(dAtA, i, uint64(len(m.CommandList[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
 	}
-	return n
+	if m.Sequence != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Author != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *Certification) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Certification) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Certification) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Certification) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
 	if len(m.Signatures) > 0 {
-		for _, b := range m.Signatures {
-			l = len(b)
-			n += 1 + l + sovMessages(uint64(l))
+		for iNdEx := len(m.Signatures) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Signatures[iNdEx])
+			copy(dAtA[i:], m.Signatures[iNdEx])
+			i = encodeVarintMessages(dAtA, i, uint64(len(m.Signatures[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Vote) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Vote) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Vote) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Certification != nil {
+		{
+			size, err := m.Certification.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Author != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CertEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CertEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CertEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Cert != nil {
+		{
+			size, err := m.Cert.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ID != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.ID))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QuorumCert) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QuorumCert) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QuorumCert) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.AggSignature) > 0 {
+		i -= len(m.AggSignature)
+		copy(dAtA[i:], m.AggSignature)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.AggSignature)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Signers) > 0 {
+		i -= len(m.Signers)
+		copy(dAtA[i:], m.Signers)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.Signers)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Certs) > 0 {
+		for iNdEx := len(m.Certs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Certs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMessages(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PartialOrder) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PartialOrder) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PartialOrder) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.OrderedTime != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.OrderedTime))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.QC != nil {
+		{
+			size, err := m.QC.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.PreOrder != nil {
+		{
+			size, err := m.PreOrder.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FetchPartial) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FetchPartial) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FetchPartial) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Sequence != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Generator != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Generator))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Author != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *FetchCommand) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FetchCommand) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FetchCommand) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Sequence != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Client != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Client))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Digest) > 0 {
+		i -= len(m.Digest)
+		copy(dAtA[i:], m.Digest)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.Digest)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Author != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PartialOrderBatch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PartialOrderBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PartialOrderBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Reconfiguration != nil {
+		{
+			size, err := m.Reconfiguration.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Epoch != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Epoch))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.SeqList) > 0 {
+		dAtA16 := make([]byte, len(m.SeqList)*10)
+		var j15 int
+		for _, num := range m.SeqList {
+			for num >= 1<<7 {
+				dAtA16[j15] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j15++
+			}
+			dAtA16[j15] = uint8(num)
+			j15++
+		}
+		i -= j15
+		copy(dAtA[i:], dAtA16[:j15])
+		i = encodeVarintMessages(dAtA, i, uint64(j15))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.HighOrders) > 0 {
+		for iNdEx := len(m.HighOrders) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.HighOrders[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMessages(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Author != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Author))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MisbehaviorProof) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MisbehaviorProof) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MisbehaviorProof) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.SecondCommand != nil {
+		{
+			size, err := m.SecondCommand.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.FirstCommand != nil {
+		{
+			size, err := m.FirstCommand.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.SecondPartial != nil {
+		{
+			size, err := m.SecondPartial.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.FirstPartial != nil {
+		{
+			size, err := m.FirstPartial.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.SecondPreOrder != nil {
+		{
+			size, err := m.SecondPreOrder.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.FirstPreOrder != nil {
+		{
+			size, err := m.FirstPreOrder.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Sequence != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Sequence))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Offender != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Offender))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Type != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ReplicaInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReplicaInfo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReplicaInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Possession != nil {
+		{
+			size, err := m.Possession.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.PublicKey) > 0 {
+		i -= len(m.PublicKey)
+		copy(dAtA[i:], m.PublicKey)
+		i = encodeVarintMessages(dAtA, i, uint64(len(m.PublicKey)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ID != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.ID))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Reconfiguration) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Reconfiguration) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Reconfiguration) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.QC != nil {
+		{
+			size, err := m.QC.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Replicas) > 0 {
+		for iNdEx := len(m.Replicas) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Replicas[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMessages(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Epoch != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Epoch))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WALSnapshot) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WALSnapshot) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WALSnapshot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.CommitNo) > 0 {
+		dAtA26 := make([]byte, len(m.CommitNo)*10)
+		var j25 int
+		for _, num := range m.CommitNo {
+			for num >= 1<<7 {
+				dAtA26[j25] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j25++
+			}
+			dAtA26[j25] = uint8(num)
+			j25++
+		}
+		i -= j25
+		copy(dAtA[i:], dAtA26[:j25])
+		i = encodeVarintMessages(dAtA, i, uint64(j25))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Pending) > 0 {
+		for iNdEx := len(m.Pending) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Pending[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintMessages(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.HighOrder != nil {
+		{
+			size, err := m.HighOrder.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *WALEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WALEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WALEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Snapshot != nil {
+		{
+			size, err := m.Snapshot.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Reconfiguration != nil {
+		{
+			size, err := m.Reconfiguration.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.CommitNo) > 0 {
+		dAtA31 := make([]byte, len(m.CommitNo)*10)
+		var j30 int
+		for _, num := range m.CommitNo {
+			for num >= 1<<7 {
+				dAtA31[j30] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j30++
+			}
+			dAtA31[j30] = uint8(num)
+			j30++
+		}
+		i -= j30
+		copy(dAtA[i:], dAtA31[:j30])
+		i = encodeVarintMessages(dAtA, i, uint64(j30))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Partial != nil {
+		{
+			size, err := m.Partial.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.PreOrder != nil {
+		{
+			size, err := m.PreOrder.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintMessages(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Type != 0 {
+		i = encodeVarintMessages(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintMessages(dAtA []byte, offset int, v uint64) int {
+	offset -= sovMessages(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Transaction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovMessages(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *Command) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Author != 0 {
+		n += 1 + sovMessages(uint64(m.Author))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovMessages(uint64(m.Sequence))
+	}
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if len(m.Content) > 0 {
+		for _, e := range m.Content {
+			l = e.Size()
+			n += 1 + l + sovMessages(uint64(l))
+		}
+	}
+	if len(m.HashList) > 0 {
+		for _, s := range m.HashList {
+			l = len(s)
+			n += 1 + l + sovMessages(uint64(l))
+		}
+	}
+	if m.GTime != 0 {
+		n += 1 + sovMessages(uint64(m.GTime))
+	}
+	if m.FrontRunner != nil {
+		l = m.FrontRunner.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Signature != nil {
+		l = m.Signature.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *CommandProtoIndex) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Author != 0 {
+		n += 1 + sovMessages(uint64(m.Author))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovMessages(uint64(m.Sequence))
+	}
+	return n
+}
+
+func (m *ConsensusMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovMessages(uint64(m.Type))
+	}
+	if m.From != 0 {
+		n += 1 + sovMessages(uint64(m.From))
+	}
+	if m.To != 0 {
+		n += 1 + sovMessages(uint64(m.To))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *PreOrder) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Author != 0 {
+		n += 1 + sovMessages(uint64(m.Author))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovMessages(uint64(m.Sequence))
+	}
+	if len(m.CommandList) > 0 {
+		for _, s := range m.CommandList {
+			l = len(s)
+			n += 1 + l + sovMessages(uint64(l))
+		}
+	}
+	if len(m.TimestampList) > 0 {
+		l = 0
+		for _, e := range m.TimestampList {
+			l += sovMessages(uint64(e))
+		}
+		n += 1 + sovMessages(uint64(l)) + l
+	}
+	l = len(m.ParentDigest)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Signature != nil {
+		l = m.Signature.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if len(m.ClientList) > 0 {
+		l = 0
+		for _, e := range m.ClientList {
+			l += sovMessages(uint64(e))
+		}
+		n += 1 + sovMessages(uint64(l)) + l
+	}
+	if len(m.ClientSeqList) > 0 {
+		l = 0
+		for _, e := range m.ClientSeqList {
+			l += sovMessages(uint64(e))
+		}
+		n += 1 + sovMessages(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *Certification) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Signatures) > 0 {
+		for _, b := range m.Signatures {
+			l = len(b)
+			n += 1 + l + sovMessages(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Vote) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Author != 0 {
+		n += 1 + sovMessages(uint64(m.Author))
+	}
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Certification != nil {
+		l = m.Certification.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *CertEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ID != 0 {
+		n += 1 + sovMessages(uint64(m.ID))
+	}
+	if m.Cert != nil {
+		l = m.Cert.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *QuorumCert) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Certs) > 0 {
+		for _, e := range m.Certs {
+			l = e.Size()
+			n += 1 + l + sovMessages(uint64(l))
+		}
+	}
+	l = len(m.Signers)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	l = len(m.AggSignature)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *PartialOrder) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.PreOrder != nil {
+		l = m.PreOrder.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.QC != nil {
+		l = m.QC.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.OrderedTime != 0 {
+		n += 1 + sovMessages(uint64(m.OrderedTime))
+	}
+	return n
+}
+
+func (m *FetchPartial) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Author != 0 {
+		n += 1 + sovMessages(uint64(m.Author))
+	}
+	if m.Generator != 0 {
+		n += 1 + sovMessages(uint64(m.Generator))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovMessages(uint64(m.Sequence))
+	}
+	return n
+}
+
+func (m *FetchCommand) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Author != 0 {
+		n += 1 + sovMessages(uint64(m.Author))
+	}
+	l = len(m.Digest)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Client != 0 {
+		n += 1 + sovMessages(uint64(m.Client))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovMessages(uint64(m.Sequence))
+	}
+	return n
+}
+
+func (m *PartialOrderBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Author != 0 {
+		n += 1 + sovMessages(uint64(m.Author))
+	}
+	if len(m.HighOrders) > 0 {
+		for _, e := range m.HighOrders {
+			l = e.Size()
+			n += 1 + l + sovMessages(uint64(l))
+		}
+	}
+	if len(m.SeqList) > 0 {
+		l = 0
+		for _, e := range m.SeqList {
+			l += sovMessages(uint64(e))
+		}
+		n += 1 + sovMessages(uint64(l)) + l
+	}
+	if m.Epoch != 0 {
+		n += 1 + sovMessages(uint64(m.Epoch))
+	}
+	if m.Reconfiguration != nil {
+		l = m.Reconfiguration.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *MisbehaviorProof) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovMessages(uint64(m.Type))
+	}
+	if m.Offender != 0 {
+		n += 1 + sovMessages(uint64(m.Offender))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovMessages(uint64(m.Sequence))
+	}
+	if m.FirstPreOrder != nil {
+		l = m.FirstPreOrder.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.SecondPreOrder != nil {
+		l = m.SecondPreOrder.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.FirstPartial != nil {
+		l = m.FirstPartial.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.SecondPartial != nil {
+		l = m.SecondPartial.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.FirstCommand != nil {
+		l = m.FirstCommand.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.SecondCommand != nil {
+		l = m.SecondCommand.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *ReplicaInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ID != 0 {
+		n += 1 + sovMessages(uint64(m.ID))
+	}
+	l = len(m.PublicKey)
+	if l > 0 {
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Possession != nil {
+		l = m.Possession.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *Reconfiguration) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Epoch != 0 {
+		n += 1 + sovMessages(uint64(m.Epoch))
+	}
+	if len(m.Replicas) > 0 {
+		for _, e := range m.Replicas {
+			l = e.Size()
+			n += 1 + l + sovMessages(uint64(l))
+		}
+	}
+	if m.QC != nil {
+		l = m.QC.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func (m *WALSnapshot) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.HighOrder != nil {
+		l = m.HighOrder.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if len(m.Pending) > 0 {
+		for _, e := range m.Pending {
+			l = e.Size()
+			n += 1 + l + sovMessages(uint64(l))
+		}
+	}
+	if len(m.CommitNo) > 0 {
+		l = 0
+		for _, e := range m.CommitNo {
+			l += sovMessages(uint64(e))
+		}
+		n += 1 + sovMessages(uint64(l)) + l
+	}
+	return n
+}
+
+func (m *WALEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovMessages(uint64(m.Type))
+	}
+	if m.PreOrder != nil {
+		l = m.PreOrder.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Partial != nil {
+		l = m.Partial.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if len(m.CommitNo) > 0 {
+		l = 0
+		for _, e := range m.CommitNo {
+			l += sovMessages(uint64(e))
+		}
+		n += 1 + sovMessages(uint64(l)) + l
+	}
+	if m.Reconfiguration != nil {
+		l = m.Reconfiguration.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	if m.Snapshot != nil {
+		l = m.Snapshot.Size()
+		n += 1 + l + sovMessages(uint64(l))
+	}
+	return n
+}
+
+func sovMessages(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozMessages(x uint64) (n int) {
+	return sovMessages(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Transaction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Transaction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Transaction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Command) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Command: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Command: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			m.Author = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Author |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Content", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Content = append(m.Content, &Transaction{})
+			if err := m.Content[len(m.Content)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HashList", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HashList = append(m.HashList, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GTime", wireType)
+			}
+			m.GTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FrontRunner", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.FrontRunner == nil {
+				m.FrontRunner = &CommandProtoIndex{}
+			}
+			if err := m.FrontRunner.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Signature == nil {
+				m.Signature = &Certification{}
+			}
+			if err := m.Signature.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CommandProtoIndex) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CommandProtoIndex: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CommandProtoIndex: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			m.Author = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Author |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ConsensusMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ConsensusMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= MessageType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			m.From = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.From |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
+			}
+			m.To = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.To |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PreOrder) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PreOrder: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PreOrder: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			m.Author = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Author |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommandList", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CommandList = append(m.CommandList, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType == 0 {
+				var v int64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.TimestampList = append(m.TimestampList, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMessages
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMessages
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.TimestampList) == 0 {
+					m.TimestampList = make([]int64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessages
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.TimestampList = append(m.TimestampList, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampList", wireType)
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ParentDigest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ParentDigest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Signature == nil {
+				m.Signature = &Certification{}
+			}
+			if err := m.Signature.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.ClientList = append(m.ClientList, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMessages
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMessages
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.ClientList) == 0 {
+					m.ClientList = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessages
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.ClientList = append(m.ClientList, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientList", wireType)
+			}
+		case 9:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.ClientSeqList = append(m.ClientSeqList, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMessages
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMessages
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.ClientSeqList) == 0 {
+					m.ClientSeqList = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessages
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.ClientSeqList = append(m.ClientSeqList, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientSeqList", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Certification) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Certification: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Certification: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signatures = append(m.Signatures, make([]byte, postIndex-iNdEx))
+			copy(m.Signatures[len(m.Signatures)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Vote) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Vote: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Vote: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			m.Author = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Author |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Certification", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Certification == nil {
+				m.Certification = &Certification{}
+			}
+			if err := m.Certification.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
 
-func (m *Vote) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Author != 0 {
-		n += 1 + sovMessages(uint64(m.Author))
-	}
-	l = len(m.Digest)
-	if l > 0 {
-		n += 1 + l + sovMessages(uint64(l))
-	}
-	if m.Certification != nil {
-		l = m.Certification.Size()
-		n += 1 + l + sovMessages(uint64(l))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
+	return nil
 }
-
-func (m *QuorumCert) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Certs) > 0 {
-		for k, v := range m.Certs {
-			_ = k
-			_ = v
-			l = 0
-			if v != nil {
-				l = v.Size()
-				l += 1 + sovMessages(uint64(l))
+func (m *CertEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			mapEntrySize := 1 + sovMessages(uint64(k)) + l
-			n += mapEntrySize + 1 + sovMessages(uint64(mapEntrySize))
 		}
-	}
-	return n
-}
-
-func (m *PartialOrder) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.PreOrder != nil {
-		l = m.PreOrder.Size()
-		n += 1 + l + sovMessages(uint64(l))
-	}
-	if m.QC != nil {
-		l = m.QC.Size()
-		n += 1 + l + sovMessages(uint64(l))
-	}
-	if m.OrderedTime != 0 {
-		n += 1 + sovMessages(uint64(m.OrderedTime))
-	}
-	return n
-}
-
-func (m *PartialOrderBatch) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Author != 0 {
-		n += 1 + sovMessages(uint64(m.Author))
-	}
-	if len(m.HighOrders) > 0 {
-		for _, e := range m.HighOrders {
-			l = e.Size()
-			n += 1 + l + sovMessages(uint64(l))
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CertEntry: wiretype end group for non-group")
 		}
-	}
-	if len(m.SeqList) > 0 {
-		l = 0
-		for _, e := range m.SeqList {
-			l += sovMessages(uint64(e))
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CertEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			m.ID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ID |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cert", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Cert == nil {
+				m.Cert = &Certification{}
+			}
+			if err := m.Cert.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
-		n += 1 + sovMessages(uint64(l)) + l
 	}
-	return n
-}
 
-func sovMessages(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozMessages(x uint64) (n int) {
-	return sovMessages(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *Transaction) Unmarshal(dAtA []byte) error {
+func (m *QuorumCert) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1518,17 +4591,17 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Transaction: wiretype end group for non-group")
+			return fmt.Errorf("proto: QuorumCert: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Transaction: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QuorumCert: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Certs", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -1538,27 +4611,29 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMessages
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Hash = string(dAtA[iNdEx:postIndex])
+			m.Certs = append(m.Certs, &CertEntry{})
+			if err := m.Certs[len(m.Certs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Signers", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1585,16 +4660,16 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
+			m.Signers = append(m.Signers[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signers == nil {
+				m.Signers = []byte{}
 			}
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AggSignature", wireType)
 			}
-			m.Timestamp = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -1604,21 +4679,33 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Timestamp |= int64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AggSignature = append(m.AggSignature[:0], dAtA[iNdEx:postIndex]...)
+			if m.AggSignature == nil {
+				m.AggSignature = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessages(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -1633,7 +4720,7 @@ func (m *Transaction) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Command) Unmarshal(dAtA []byte) error {
+func (m *PartialOrder) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1656,55 +4743,17 @@ func (m *Command) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Command: wiretype end group for non-group")
+			return fmt.Errorf("proto: PartialOrder: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Command: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PartialOrder: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
-			}
-			m.Author = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMessages
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Author |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
-			}
-			m.Sequence = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMessages
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Sequence |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PreOrder", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -1714,27 +4763,31 @@ func (m *Command) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMessages
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Digest = string(dAtA[iNdEx:postIndex])
+			if m.PreOrder == nil {
+				m.PreOrder = &PreOrder{}
+			}
+			if err := m.PreOrder.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 4:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Content", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field QC", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1761,16 +4814,18 @@ func (m *Command) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Content = append(m.Content, &Transaction{})
-			if err := m.Content[len(m.Content)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.QC == nil {
+				m.QC = &QuorumCert{}
+			}
+			if err := m.QC.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HashList", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderedTime", wireType)
 			}
-			var stringLen uint64
+			m.OrderedTime = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -1780,29 +4835,66 @@ func (m *Command) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.OrderedTime |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthMessages
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMessages(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.HashList = append(m.HashList, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
-		case 6:
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FetchPartial) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMessages
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FetchPartial: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FetchPartial: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field GTime", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
 			}
-			m.GTime = 0
+			m.Author = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -1812,16 +4904,16 @@ func (m *Command) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.GTime |= int64(b&0x7F) << shift
+				m.Author |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FrontRunner", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Generator", wireType)
 			}
-			var msglen int
+			m.Generator = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -1831,38 +4923,37 @@ func (m *Command) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Generator |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthMessages
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.FrontRunner == nil {
-				m.FrontRunner = &CommandProtoIndex{}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
 			}
-			if err := m.FrontRunner.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessages(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -1877,7 +4968,7 @@ func (m *Command) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CommandProtoIndex) Unmarshal(dAtA []byte) error {
+func (m *FetchCommand) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1900,17 +4991,68 @@ func (m *CommandProtoIndex) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CommandProtoIndex: wiretype end group for non-group")
+			return fmt.Errorf("proto: FetchCommand: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CommandProtoIndex: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: FetchCommand: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			m.Author = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Author |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Digest = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Client", wireType)
 			}
-			m.Author = 0
+			m.Client = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -1920,12 +5062,12 @@ func (m *CommandProtoIndex) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Author |= uint64(b&0x7F) << shift
+				m.Client |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
+		case 4:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
 			}
@@ -1950,10 +5092,7 @@ func (m *CommandProtoIndex) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -1968,7 +5107,7 @@ func (m *CommandProtoIndex) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
+func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1991,17 +5130,17 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ConsensusMessage: wiretype end group for non-group")
+			return fmt.Errorf("proto: PartialOrderBatch: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ConsensusMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PartialOrderBatch: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
 			}
-			m.Type = 0
+			m.Author = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2011,16 +5150,16 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Type |= MessageType(b&0x7F) << shift
+				m.Author |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HighOrders", wireType)
 			}
-			m.From = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2030,16 +5169,107 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.From |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HighOrders = append(m.HighOrders, &PartialOrder{})
+			if err := m.HighOrders[len(m.HighOrders)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 3:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.SeqList = append(m.SeqList, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMessages
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMessages
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.SeqList) == 0 {
+					m.SeqList = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessages
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.SeqList = append(m.SeqList, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field SeqList", wireType)
+			}
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Epoch", wireType)
 			}
-			m.To = 0
+			m.Epoch = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2049,16 +5279,16 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.To |= uint64(b&0x7F) << shift
+				m.Epoch |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Reconfiguration", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2068,24 +5298,26 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMessages
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
-			if m.Payload == nil {
-				m.Payload = []byte{}
+			if m.Reconfiguration == nil {
+				m.Reconfiguration = &Reconfiguration{}
+			}
+			if err := m.Reconfiguration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -2094,10 +5326,7 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -2112,7 +5341,7 @@ func (m *ConsensusMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PreOrder) Unmarshal(dAtA []byte) error {
+func (m *MisbehaviorProof) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2135,17 +5364,17 @@ func (m *PreOrder) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PreOrder: wiretype end group for non-group")
+			return fmt.Errorf("proto: MisbehaviorProof: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PreOrder: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: MisbehaviorProof: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			var stringLen uint64
+			m.Type = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2155,29 +5384,16 @@ func (m *PreOrder) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Type |= MisbehaviorType(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthMessages
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Digest = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Offender", wireType)
 			}
-			m.Author = 0
+			m.Offender = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2187,7 +5403,7 @@ func (m *PreOrder) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Author |= uint64(b&0x7F) << shift
+				m.Offender |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2213,9 +5429,9 @@ func (m *PreOrder) Unmarshal(dAtA []byte) error {
 			}
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CommandList", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstPreOrder", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2225,105 +5441,69 @@ func (m *PreOrder) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMessages
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CommandList = append(m.CommandList, string(dAtA[iNdEx:postIndex]))
+			if m.FirstPreOrder == nil {
+				m.FirstPreOrder = &PreOrder{}
+			}
+			if err := m.FirstPreOrder.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 5:
-			if wireType == 0 {
-				var v int64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowMessages
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					v |= int64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				m.TimestampList = append(m.TimestampList, v)
-			} else if wireType == 2 {
-				var packedLen int
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowMessages
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					packedLen |= int(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				if packedLen < 0 {
-					return ErrInvalidLengthMessages
-				}
-				postIndex := iNdEx + packedLen
-				if postIndex < 0 {
-					return ErrInvalidLengthMessages
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecondPreOrder", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
 				}
-				if postIndex > l {
+				if iNdEx >= l {
 					return io.ErrUnexpectedEOF
 				}
-				var elementCount int
-				var count int
-				for _, integer := range dAtA[iNdEx:postIndex] {
-					if integer < 128 {
-						count++
-					}
-				}
-				elementCount = count
-				if elementCount != 0 && len(m.TimestampList) == 0 {
-					m.TimestampList = make([]int64, 0, elementCount)
-				}
-				for iNdEx < postIndex {
-					var v int64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowMessages
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						v |= int64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					m.TimestampList = append(m.TimestampList, v)
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
-			} else {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimestampList", wireType)
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.SecondPreOrder == nil {
+				m.SecondPreOrder = &PreOrder{}
+			}
+			if err := m.SecondPreOrder.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ParentDigest", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstPartial", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2333,82 +5513,105 @@ func (m *PreOrder) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMessages
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ParentDigest = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipMessages(dAtA[iNdEx:])
-			if err != nil {
+			if m.FirstPartial == nil {
+				m.FirstPartial = &PartialOrder{}
+			}
+			if err := m.FirstPartial.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			if skippy < 0 {
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecondPartial", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
 				return ErrInvalidLengthMessages
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
 				return ErrInvalidLengthMessages
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Certification) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowMessages
+			if m.SecondPartial == nil {
+				m.SecondPartial = &PartialOrder{}
 			}
-			if iNdEx >= l {
+			if err := m.SecondPartial.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstCommand", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if m.FirstCommand == nil {
+				m.FirstCommand = &Command{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Certification: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Certification: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			if err := m.FirstCommand.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SecondCommand", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2418,23 +5621,27 @@ func (m *Certification) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthMessages
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Signatures = append(m.Signatures, make([]byte, postIndex-iNdEx))
-			copy(m.Signatures[len(m.Signatures)-1], dAtA[iNdEx:postIndex])
+			if m.SecondCommand == nil {
+				m.SecondCommand = &Command{}
+			}
+			if err := m.SecondCommand.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2442,10 +5649,7 @@ func (m *Certification) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -2460,7 +5664,7 @@ func (m *Certification) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Vote) Unmarshal(dAtA []byte) error {
+func (m *ReplicaInfo) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2483,17 +5687,17 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Vote: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReplicaInfo: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Vote: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReplicaInfo: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
 			}
-			m.Author = 0
+			m.ID = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2503,16 +5707,16 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Author |= uint64(b&0x7F) << shift
+				m.ID |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Digest", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PublicKey", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2522,27 +5726,29 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthMessages
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Digest = string(dAtA[iNdEx:postIndex])
+			m.PublicKey = append(m.PublicKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.PublicKey == nil {
+				m.PublicKey = []byte{}
+			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Certification", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Possession", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2569,10 +5775,10 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Certification == nil {
-				m.Certification = &Certification{}
+			if m.Possession == nil {
+				m.Possession = &Certification{}
 			}
-			if err := m.Certification.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Possession.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2582,10 +5788,7 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -2600,7 +5803,7 @@ func (m *Vote) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QuorumCert) Unmarshal(dAtA []byte) error {
+func (m *Reconfiguration) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2623,15 +5826,34 @@ func (m *QuorumCert) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QuorumCert: wiretype end group for non-group")
+			return fmt.Errorf("proto: Reconfiguration: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QuorumCert: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Reconfiguration: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Epoch", wireType)
+			}
+			m.Epoch = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Epoch |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Certs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Replicas", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2658,91 +5880,46 @@ func (m *QuorumCert) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Certs == nil {
-				m.Certs = make(map[uint64]*Certification)
+			m.Replicas = append(m.Replicas, &ReplicaInfo{})
+			if err := m.Replicas[len(m.Replicas)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			var mapkey uint64
-			var mapvalue *Certification
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowMessages
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QC", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowMessages
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-				} else if fieldNum == 2 {
-					var mapmsglen int
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowMessages
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						mapmsglen |= int(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					if mapmsglen < 0 {
-						return ErrInvalidLengthMessages
-					}
-					postmsgIndex := iNdEx + mapmsglen
-					if postmsgIndex < 0 {
-						return ErrInvalidLengthMessages
-					}
-					if postmsgIndex > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvalue = &Certification{}
-					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
-						return err
-					}
-					iNdEx = postmsgIndex
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipMessages(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthMessages
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
 				}
 			}
-			m.Certs[mapkey] = mapvalue
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.QC == nil {
+				m.QC = &QuorumCert{}
+			}
+			if err := m.QC.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2750,10 +5927,7 @@ func (m *QuorumCert) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -2768,7 +5942,7 @@ func (m *QuorumCert) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PartialOrder) Unmarshal(dAtA []byte) error {
+func (m *WALSnapshot) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2791,15 +5965,15 @@ func (m *PartialOrder) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PartialOrder: wiretype end group for non-group")
+			return fmt.Errorf("proto: WALSnapshot: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PartialOrder: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: WALSnapshot: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PreOrder", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field HighOrder", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2826,16 +6000,16 @@ func (m *PartialOrder) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.PreOrder == nil {
-				m.PreOrder = &PreOrder{}
+			if m.HighOrder == nil {
+				m.HighOrder = &PreOrder{}
 			}
-			if err := m.PreOrder.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.HighOrder.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QC", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Pending", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2862,31 +6036,86 @@ func (m *PartialOrder) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.QC == nil {
-				m.QC = &QuorumCert{}
-			}
-			if err := m.QC.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Pending = append(m.Pending, &PreOrder{})
+			if err := m.Pending[len(m.Pending)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OrderedTime", wireType)
-			}
-			m.OrderedTime = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowMessages
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-				if iNdEx >= l {
+				m.CommitNo = append(m.CommitNo, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessages
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMessages
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthMessages
+				}
+				if postIndex > l {
 					return io.ErrUnexpectedEOF
 				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.OrderedTime |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.CommitNo) == 0 {
+					m.CommitNo = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessages
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.CommitNo = append(m.CommitNo, v)
 				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitNo", wireType)
 			}
 		default:
 			iNdEx = preIndex
@@ -2894,10 +6123,7 @@ func (m *PartialOrder) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -2912,7 +6138,7 @@ func (m *PartialOrder) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
+func (m *WALEntry) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2935,17 +6161,17 @@ func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PartialOrderBatch: wiretype end group for non-group")
+			return fmt.Errorf("proto: WALEntry: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PartialOrderBatch: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: WALEntry: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			m.Author = 0
+			m.Type = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowMessages
@@ -2955,14 +6181,14 @@ func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Author |= uint64(b&0x7F) << shift
+				m.Type |= WALEntryType(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HighOrders", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PreOrder", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2989,12 +6215,50 @@ func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.HighOrders = append(m.HighOrders, &PartialOrder{})
-			if err := m.HighOrders[len(m.HighOrders)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.PreOrder == nil {
+				m.PreOrder = &PreOrder{}
+			}
+			if err := m.PreOrder.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Partial", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Partial == nil {
+				m.Partial = &PartialOrder{}
+			}
+			if err := m.Partial.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
 			if wireType == 0 {
 				var v uint64
 				for shift := uint(0); ; shift += 7 {
@@ -3011,7 +6275,7 @@ func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
 						break
 					}
 				}
-				m.SeqList = append(m.SeqList, v)
+				m.CommitNo = append(m.CommitNo, v)
 			} else if wireType == 2 {
 				var packedLen int
 				for shift := uint(0); ; shift += 7 {
@@ -3046,8 +6310,8 @@ func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
 					}
 				}
 				elementCount = count
-				if elementCount != 0 && len(m.SeqList) == 0 {
-					m.SeqList = make([]uint64, 0, elementCount)
+				if elementCount != 0 && len(m.CommitNo) == 0 {
+					m.CommitNo = make([]uint64, 0, elementCount)
 				}
 				for iNdEx < postIndex {
 					var v uint64
@@ -3065,21 +6329,90 @@ func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
 							break
 						}
 					}
-					m.SeqList = append(m.SeqList, v)
+					m.CommitNo = append(m.CommitNo, v)
 				}
 			} else {
-				return fmt.Errorf("proto: wrong wireType = %d for field SeqList", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitNo", wireType)
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reconfiguration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Reconfiguration == nil {
+				m.Reconfiguration = &Reconfiguration{}
+			}
+			if err := m.Reconfiguration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Snapshot", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessages
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessages
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessages
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
+			if m.Snapshot == nil {
+				m.Snapshot = &WALSnapshot{}
+			}
+			if err := m.Snapshot.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessages(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessages
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessages
 			}
 			if (iNdEx + skippy) > l {
@@ -3097,6 +6430,7 @@ func (m *PartialOrderBatch) Unmarshal(dAtA []byte) error {
 func skipMessages(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -3128,10 +6462,8 @@ func skipMessages(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -3152,55 +6484,30 @@ func skipMessages(dAtA []byte) (n int, err error) {
 				return 0, ErrInvalidLengthMessages
 			}
 			iNdEx += length
-			if iNdEx < 0 {
-				return 0, ErrInvalidLengthMessages
-			}
-			return iNdEx, nil
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowMessages
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipMessages(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-				if iNdEx < 0 {
-					return 0, ErrInvalidLengthMessages
-				}
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupMessages
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthMessages
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthMessages = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowMessages   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthMessages        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowMessages          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupMessages = fmt.Errorf("proto: unexpected end of group")
 )
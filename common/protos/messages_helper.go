@@ -2,6 +2,7 @@ package protos
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -38,6 +39,46 @@ func PackPartialOrder(qc *PartialOrder) (*ConsensusMessage, error) {
 	return NewConsensusMessage(MessageType_QUORUM_CERT, qc.Author(), 0, payload), nil
 }
 
+func PackFetchPartial(fetch *FetchPartial) (*ConsensusMessage, error) {
+	payload, err := proto.Marshal(fetch)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsensusMessage(MessageType_FETCH_PARTIAL, fetch.Author, 0, payload), nil
+}
+
+func PackReturnPartial(pOrder *PartialOrder, from, to uint64) (*ConsensusMessage, error) {
+	payload, err := proto.Marshal(pOrder)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsensusMessage(MessageType_RETURN_PARTIAL, from, to, payload), nil
+}
+
+func PackFetchCommand(fetch *FetchCommand, to uint64) (*ConsensusMessage, error) {
+	payload, err := proto.Marshal(fetch)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsensusMessage(MessageType_FETCH_COMMAND, fetch.Author, to, payload), nil
+}
+
+func PackReturnCommand(command *Command, from, to uint64) (*ConsensusMessage, error) {
+	payload, err := proto.Marshal(command)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsensusMessage(MessageType_RETURN_COMMAND, from, to, payload), nil
+}
+
+func PackMisbehavior(proof *MisbehaviorProof, from uint64) (*ConsensusMessage, error) {
+	payload, err := proto.Marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+	return NewConsensusMessage(MessageType_MISBEHAVIOR, from, 0, payload), nil
+}
+
 //=============================== Command ===============================================
 
 func (m *Command) Less(item btree.Item) bool {
@@ -86,6 +127,14 @@ func (m *PartialOrder) TimestampList() []int64 {
 	return m.PreOrder.TimestampList
 }
 
+func (m *PartialOrder) ClientList() []uint64 {
+	return m.PreOrder.ClientList
+}
+
+func (m *PartialOrder) ClientSeqList() []uint64 {
+	return m.PreOrder.ClientSeqList
+}
+
 func (m *PartialOrder) SetOrderedTime() {
 	m.OrderedTime = time.Now().UnixNano()
 }
@@ -98,16 +147,141 @@ func (m *PartialOrder) ParentDigest() string {
 	return m.PreOrder.ParentDigest
 }
 
+//=================================== Fetch Missing =========================================
+
+func (m *FetchPartial) Format() string {
+	return fmt.Sprintf("[FetchPartial: author %d, generator %d, sequence %d]", m.Author, m.Generator, m.Sequence)
+}
+
+func (m *FetchCommand) Format() string {
+	if m.Digest == "" {
+		return fmt.Sprintf("[FetchCommand: author %d, client %d, sequence %d]", m.Author, m.Client, m.Sequence)
+	}
+	return fmt.Sprintf("[FetchCommand: author %d, digest %s]", m.Author, m.Digest)
+}
+
+//=================================== Quorum Cert =========================================
+
+// AddCert records the signature of signer, and the certs are kept in ascending order of signers.
+// It returns false if the signer has been recorded before, whose signature is replaced.
+func (m *QuorumCert) AddCert(id uint64, cert *Certification) bool {
+	index := sort.Search(len(m.Certs), func(i int) bool { return m.Certs[i].ID >= id })
+	if index < len(m.Certs) && m.Certs[index].ID == id {
+		m.Certs[index].Cert = cert
+		return false
+	}
+	m.Certs = append(m.Certs, nil)
+	copy(m.Certs[index+1:], m.Certs[index:])
+	m.Certs[index] = &CertEntry{ID: id, Cert: cert}
+	return true
+}
+
+// CheckCanonical checks if the certs are in strictly ascending order of signers,
+// which also makes sure there isn't any duplicated signer.
+func (m *QuorumCert) CheckCanonical() error {
+	for index, entry := range m.Certs {
+		if entry == nil || entry.Cert == nil {
+			return fmt.Errorf("nil cert at index %d", index)
+		}
+		if index > 0 && m.Certs[index-1].ID >= entry.ID {
+			return fmt.Errorf("certs are not sorted by signers, %d is not larger than %d", entry.ID, m.Certs[index-1].ID)
+		}
+	}
+	return nil
+}
+
+// IsAggregated returns whether current quorum-cert is encoded in compact mode with an aggregated signature.
+func (m *QuorumCert) IsAggregated() bool {
+	return len(m.AggSignature) > 0
+}
+
+// SetSigners encodes the identifiers of signers into the bitmap.
+func (m *QuorumCert) SetSigners(signers []uint64) {
+	var bitmap []byte
+	for _, id := range signers {
+		for uint64(len(bitmap)) <= id/8 {
+			bitmap = append(bitmap, 0)
+		}
+		bitmap[id/8] |= 1 << (id % 8)
+	}
+	m.Signers = bitmap
+}
+
+// SignerList decodes the bitmap into the sorted identifiers of signers.
+func (m *QuorumCert) SignerList() []uint64 {
+	var signers []uint64
+	for i, b := range m.Signers {
+		for j := uint64(0); j < 8; j++ {
+			if b&(1<<j) != 0 {
+				signers = append(signers, uint64(i)*8+j)
+			}
+		}
+	}
+	return signers
+}
+
 //=================================== Partial Order Batch =========================================
 
 func (m *PartialOrderBatch) Format() string {
-	return fmt.Sprintf("[PartialBatch: author %d, proposed nos %v]", m.Author, m.SeqList)
+	return fmt.Sprintf("[PartialBatch: author %d, epoch %d, proposed nos %v]", m.Author, m.Epoch, m.SeqList)
+}
+
+// CheckCanonical checks if the quorum-certs of high-orders are encoded in canonical form,
+// so that the digest of current batch is stable across replicas.
+func (m *PartialOrderBatch) CheckCanonical() error {
+	for index, pOrder := range m.HighOrders {
+		if pOrder == nil || pOrder.QC == nil {
+			continue
+		}
+		if err := pOrder.QC.CheckCanonical(); err != nil {
+			return fmt.Errorf("high-order at index %d: %s", index, err)
+		}
+	}
+	return nil
+}
+
+//=================================== Misbehavior =========================================
+
+func (m *MisbehaviorProof) Format() string {
+	return fmt.Sprintf("[MisbehaviorProof: type %s, offender %d, sequence %d]", m.Type, m.Offender, m.Sequence)
+}
+
+// ConflictingPreOrders returns the conflicting pre-orders in current proof.
+func (m *MisbehaviorProof) ConflictingPreOrders() (*PreOrder, *PreOrder) {
+	if m.Type == MisbehaviorType_PARTIAL_ORDER_EQUIVOCATION {
+		if m.FirstPartial == nil || m.SecondPartial == nil {
+			return nil, nil
+		}
+		return m.FirstPartial.PreOrder, m.SecondPartial.PreOrder
+	}
+	return m.FirstPreOrder, m.SecondPreOrder
+}
+
+// IsClientMisbehavior returns if the offender of current proof is a client instead of a participant.
+func (m *MisbehaviorProof) IsClientMisbehavior() bool {
+	return m.Type == MisbehaviorType_CLIENT_EQUIVOCATION
+}
+
+//=================================== Reconfiguration =========================================
+
+func (m *Reconfiguration) Format() string {
+	return fmt.Sprintf("[Reconfiguration: epoch %d, members %v]", m.Epoch, m.Members())
+}
+
+// Members returns the sorted identifiers of participants in the new membership.
+func (m *Reconfiguration) Members() []uint64 {
+	members := make([]uint64, 0, len(m.Replicas))
+	for _, replica := range m.Replicas {
+		members = append(members, replica.ID)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+	return members
 }
 
 //=================================== Generate Messages ============================================
 
 func NewQuorumCert() *QuorumCert {
-	return &QuorumCert{Certs: make(map[uint64]*Certification)}
+	return &QuorumCert{}
 }
 
 func NewPartialOrder(pre *PreOrder) *PartialOrder {
@@ -118,11 +292,11 @@ func NewNopPartialOrder() *PartialOrder {
 	return &PartialOrder{PreOrder: NewNopPreOrder(), QC: NewQuorumCert()}
 }
 
-func NewPreOrder(author uint64, sequence uint64, commandList []string, timestampList []int64, previous *PreOrder) *PreOrder {
+func NewPreOrder(author uint64, sequence uint64, commandList []string, timestampList []int64, clientList []uint64, clientSeqList []uint64, previous *PreOrder) *PreOrder {
 	if previous == nil {
 		previous = &PreOrder{Digest: "GENESIS PRE ORDER"}
 	}
-	return &PreOrder{Author: author, Sequence: sequence, CommandList: commandList, TimestampList: timestampList, ParentDigest: previous.Digest}
+	return &PreOrder{Author: author, Sequence: sequence, CommandList: commandList, TimestampList: timestampList, ClientList: clientList, ClientSeqList: clientSeqList, ParentDigest: previous.Digest}
 }
 
 func NewNopPreOrder() *PreOrder {
@@ -0,0 +1,12 @@
+package types
+
+// Checkpoint is a stable point agreed through the committed partial order batches.
+// As for that each correct node commits the same sequence of query streams, the checkpoint
+// would be the same for them, and the states below it could be garbage collected.
+type Checkpoint struct {
+	// Sequence is the number of committed query streams when current checkpoint is generated.
+	Sequence uint64
+
+	// Watermarks are the highest committed partial order sequence number for each participant.
+	Watermarks map[uint64]uint64
+}
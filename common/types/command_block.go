@@ -39,6 +39,9 @@ type FrontStream struct {
 
 	// Stream is the content of front stream.
 	Stream CommandStream
+
+	// Cycles are the sizes of the precedence cycles in current front stream.
+	Cycles []int
 }
 
 // SortableInnerBlocks is a slice of inner block to sort.
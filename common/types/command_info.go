@@ -86,6 +86,16 @@ func (ci *CommandInfo) OrderCount() int {
 	return len(ci.Orders)
 }
 
+// Referrers returns the identifiers of replicas whose partial orders have referred to current command.
+func (ci *CommandInfo) Referrers() []uint64 {
+	referrers := make([]uint64, 0, len(ci.Orders))
+	for author := range ci.Orders {
+		referrers = append(referrers, author)
+	}
+	sort.Slice(referrers, func(i, j int) bool { return referrers[i] < referrers[j] })
+	return referrers
+}
+
 //========================== Priority Command ====================================
 
 func (ci *CommandInfo) PrioriRecord(priInfo *CommandInfo) {
@@ -0,0 +1,16 @@
+package types
+
+// CommandLimit is the size limit of commands we would accept, so that a malicious proposer couldn't exhaust
+// our memory with oversized commands. A non-positive field means unlimited.
+type CommandLimit struct {
+	// MaxTxs is the max number of transactions in one command.
+	MaxTxs int
+
+	// MaxBytes is the max size of transaction payloads in one command.
+	MaxBytes int
+}
+
+// NewDefaultCommandLimit returns the default size limit of commands.
+func NewDefaultCommandLimit() CommandLimit {
+	return CommandLimit{MaxTxs: DefaultMaxCommandTxs, MaxBytes: DefaultMaxCommandBytes}
+}
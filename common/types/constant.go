@@ -4,10 +4,82 @@ import (
 	"time"
 )
 
+const (
+	// BatchFixed is the batching policy with fixed max size and max delay.
+	BatchFixed = "fixed"
+
+	// BatchAdaptive is the batching policy which tunes the delay with observed quorum latency and arrival rate.
+	BatchAdaptive = "adaptive"
+)
+
+const (
+	// StrategyPhalanxAnchor is the ordering strategy with phalanx anchor-based rules.
+	StrategyPhalanxAnchor = "phalanx-anchor"
+
+	// StrategyTimestampAnchor is the ordering strategy with timestamp anchor-based rules.
+	StrategyTimestampAnchor = "timestamp-anchor"
+
+	// StrategyTimestampBased is the ordering strategy which sorts the quorum sequenced commands with trusted timestamps.
+	StrategyTimestampBased = "timestamp-based"
+
+	// StrategyThemis is the ordering strategy with the batch-order-fairness rule of Themis.
+	StrategyThemis = "themis"
+
+	// StrategyAequitas is the ordering strategy with the γ receive-order-fairness rule of Aequitas.
+	StrategyAequitas = "aequitas"
+)
+
 const (
 	// DefaultTimeDuration is the default time duration for proposal generation.
 	DefaultTimeDuration = 50 * time.Millisecond
 
+	// DefaultMinBatchDelay is the min interval to wait for more commands with adaptive batching policy.
+	DefaultMinBatchDelay = 1 * time.Millisecond
+
+	// DefaultFetchTimeout is the default interval to wait for a committed message before fetching it from others.
+	DefaultFetchTimeout = 500 * time.Millisecond
+
+	// DefaultVoteTimeout is the default interval to wait for quorum votes before retransmitting a pre-order.
+	DefaultVoteTimeout = 1 * time.Second
+
+	// MaxRetransmission is the max number of retransmissions for a pre-order without any new votes, and the interval
+	// between them is doubled each time.
+	MaxRetransmission = 8
+
+	// DefaultGapTimeout is the default interval to wait for a missing command which stalls a client before skipping it.
+	DefaultGapTimeout = 5 * time.Second
+
+	// DefaultMaxClockSkew is the default tolerated clock skew between the timestamps in pre-orders and local time.
+	DefaultMaxClockSkew = 1 * time.Second
+
+	// DefaultMaxSeenDelay is the default max interval from the timestamp of a command to the time we have received it.
+	DefaultMaxSeenDelay = 1 * time.Second
+
+	// DefaultCheckpointInterval is the default number of committed query streams between two checkpoints.
+	DefaultCheckpointInterval uint64 = 100
+
+	// DefaultQueueSize is the default capacity of the queues from receiver to meta pool.
+	DefaultQueueSize int = 100
+
+	// DefaultStreamCacheSize is the default number of committed query streams waiting for execution.
+	DefaultStreamCacheSize int = 10000
+
+	// DefaultMaxCommandTxs is the default max number of transactions in one command.
+	DefaultMaxCommandTxs int = 10000
+
+	// DefaultMaxCommandBytes is the default max size of transaction payloads in one command.
+	DefaultMaxCommandBytes int = 16 * 1024 * 1024
+
+	// DefaultPipelineWindow is the default number of pre-orders in flight for each participant.
+	DefaultPipelineWindow int = 4
+
+	// DefaultFairnessGamma is the default fraction of partial orders to decide the precedence between two commands,
+	// which wouldn't exceed the quorum size.
+	DefaultFairnessGamma float64 = 2.0 / 3.0
+
+	// DefaultVerifyCacheSize is the default number of verified quorum-certs to cache.
+	DefaultVerifyCacheSize int = 10000
+
 	// DefaultLogRotation is the default log rotation for proposal generation.
 	DefaultLogRotation int = 10000
 
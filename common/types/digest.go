@@ -2,10 +2,10 @@ package types
 
 import (
 	"bytes"
-	"crypto/md5"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
+	"fmt"
 
 	"github.com/Grivn/phalanx/common/protos"
 	"github.com/gogo/protobuf/proto"
@@ -36,8 +36,8 @@ func (h Hash) Hex() string {
 //================================== Hash Management ===========================================
 
 // CheckDigest is used to check the correctness of digest
-func CheckDigest(pre *protos.PreOrder) error {
-	digest, err := CalculateDigest(pre)
+func CheckDigest(hasher Hasher, pre *protos.PreOrder) error {
+	digest, err := CalculateDigest(hasher, pre)
 	if err != nil {
 		return err
 	}
@@ -48,24 +48,104 @@ func CheckDigest(pre *protos.PreOrder) error {
 }
 
 // CalculateDigest is used to calculate the digest
-func CalculateDigest(pre *protos.PreOrder) (string, error) {
-	payload, err := proto.Marshal(&protos.PreOrder{Author: pre.Author, Sequence: pre.Sequence, CommandList: pre.CommandList, TimestampList: pre.TimestampList, ParentDigest: pre.ParentDigest})
+func CalculateDigest(hasher Hasher, pre *protos.PreOrder) (string, error) {
+	payload, err := proto.Marshal(&protos.PreOrder{Author: pre.Author, Sequence: pre.Sequence, CommandList: pre.CommandList, TimestampList: pre.TimestampList, ClientList: pre.ClientList, ClientSeqList: pre.ClientSeqList, ParentDigest: pre.ParentDigest})
 	if err != nil {
 		return "", err
 	}
-	return CalculatePayloadHash(payload, 0), nil
+	return CalculatePayloadHash(hasher, payload, 0), nil
+}
+
+// CalculateReconfigurationDigest is used to calculate the digest of reconfiguration which is signed by participants.
+func CalculateReconfigurationDigest(hasher Hasher, reconf *protos.Reconfiguration) (string, error) {
+	payload, err := proto.Marshal(&protos.Reconfiguration{Epoch: reconf.Epoch, Replicas: reconf.Replicas})
+	if err != nil {
+		return "", err
+	}
+	return CalculatePayloadHash(hasher, payload, 0), nil
+}
+
+// CheckCommand is used to check the size, digest and content of command.
+func CheckCommand(hasher Hasher, command *protos.Command, limit CommandLimit) error {
+	if err := CheckCommandSize(command, limit); err != nil {
+		return err
+	}
+	if err := CheckCommandDigest(hasher, command); err != nil {
+		return err
+	}
+	return CheckCommandContent(hasher, command)
+}
+
+// CheckCommandSize is used to check the command with size limit.
+func CheckCommandSize(command *protos.Command, limit CommandLimit) error {
+	if limit.MaxTxs > 0 && (len(command.HashList) > limit.MaxTxs || len(command.Content) > limit.MaxTxs) {
+		return fmt.Errorf("%w: %d transactions, limit %d", ErrCommandSize, len(command.HashList), limit.MaxTxs)
+	}
+	if limit.MaxBytes > 0 {
+		size := 0
+		for _, tx := range command.Content {
+			size += len(tx.Payload)
+		}
+		if size > limit.MaxBytes {
+			return fmt.Errorf("%w: %d bytes, limit %d", ErrCommandSize, size, limit.MaxBytes)
+		}
+	}
+	return nil
+}
+
+// CheckCommandDigest is used to check the correctness of command digest.
+func CheckCommandDigest(hasher Hasher, command *protos.Command) error {
+	digest, err := CalculateCommandDigest(hasher, command)
+	if err != nil {
+		return err
+	}
+	if digest != command.Digest {
+		return ErrCommandDigest
+	}
+	return nil
+}
+
+// CheckCommandContent is used to check the transactions in command match the hash list,
+// and the hash of each transaction is calculated from its payload.
+func CheckCommandContent(hasher Hasher, command *protos.Command) error {
+	if len(command.Content) != len(command.HashList) {
+		return ErrCommandContent
+	}
+	for index, tx := range command.Content {
+		if tx.Hash != command.HashList[index] {
+			return ErrCommandContent
+		}
+		if CalculateTransactionHash(hasher, tx) != tx.Hash {
+			return fmt.Errorf("%w: %s", ErrTransactionHash, tx.Hash)
+		}
+	}
+	return nil
+}
+
+// CalculateCommandDigest is used to calculate the digest of command
+func CalculateCommandDigest(hasher Hasher, command *protos.Command) (string, error) {
+	payload, err := proto.Marshal(&protos.Command{Author: command.Author, Sequence: command.Sequence, HashList: command.HashList})
+	if err != nil {
+		return "", err
+	}
+	return CalculatePayloadHash(hasher, payload, 0), nil
 }
 
 // GetHash returns the TransactionHash
-func GetHash(tx *protos.Transaction) string {
+func GetHash(hasher Hasher, tx *protos.Transaction) string {
 	if tx.Hash == "" {
-		tx.Hash = CalculatePayloadHash(tx.Payload, 0)
+		tx.Hash = CalculateTransactionHash(hasher, tx)
 	}
 	return tx.Hash
 }
 
-func CalculateListHash(list []string, timestamp int64) string {
-	h := md5.New()
+// CalculateTransactionHash calculates the hash of transaction with its payload and timestamp.
+func CalculateTransactionHash(hasher Hasher, tx *protos.Transaction) string {
+	return CalculatePayloadHash(hasher, tx.Payload, tx.Timestamp)
+}
+
+func CalculateListHash(hasher Hasher, list []string, timestamp int64) string {
+	h := hasher.New()
 	for _, hash := range list {
 		_, _ = h.Write([]byte(hash))
 	}
@@ -78,12 +158,13 @@ func CalculateListHash(list []string, timestamp int64) string {
 	return BytesToString(h.Sum(nil))
 }
 
-func CalculatePayloadHash(payload []byte, timestamp int64) string {
-	return BytesToString(CalculateMD5Hash(payload, timestamp))
+func CalculatePayloadHash(hasher Hasher, payload []byte, timestamp int64) string {
+	return BytesToString(CalculateHash(hasher, payload, timestamp))
 }
 
-func CalculateMD5Hash(payload []byte, timestamp int64) Hash {
-	h := md5.New()
+// CalculateHash calculates the hash of payload with given hasher.
+func CalculateHash(hasher Hasher, payload []byte, timestamp int64) Hash {
+	h := hasher.New()
 	_, _ = h.Write(payload)
 
 	if timestamp > 0 {
@@ -94,7 +175,10 @@ func CalculateMD5Hash(payload []byte, timestamp int64) Hash {
 	return h.Sum(nil)
 }
 
-func CalculateBatchHash(pBatch *protos.PartialOrderBatch) string {
-	payload, _ := proto.Marshal(pBatch)
-	return CalculatePayloadHash(payload, 0)
+// CalculateBatchHash calculates the canonical digest of partial order batch.
+// There isn't any map field in batch and the certs of quorum-cert are sorted by signers, so that the generated
+// marshaler, which encodes fields in the order of field numbers, produces the same payload on every replica.
+func CalculateBatchHash(hasher Hasher, pBatch *protos.PartialOrderBatch) string {
+	payload, _ := pBatch.Marshal()
+	return CalculatePayloadHash(hasher, payload, 0)
 }
@@ -0,0 +1,21 @@
+package types
+
+import "errors"
+
+// The errors returned by the validation of commands, and the callers could tell them apart with errors.Is.
+var (
+	// ErrCommandDigest indicates the digest isn't calculated from the author, sequence number and hash list.
+	ErrCommandDigest = errors.New("command digest is not equal")
+
+	// ErrCommandContent indicates the transactions don't match the hash list.
+	ErrCommandContent = errors.New("command content is not matched with hash list")
+
+	// ErrTransactionHash indicates the hash of a transaction isn't calculated from its payload.
+	ErrTransactionHash = errors.New("transaction hash is not equal")
+
+	// ErrCommandSize indicates the command exceeds the size limit.
+	ErrCommandSize = errors.New("command exceeds size limit")
+
+	// ErrClientEquivocation indicates the client has issued another command with the same sequence number.
+	ErrClientEquivocation = errors.New("conflicting command with the same sequence number")
+)
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"fmt"
+	"math"
+)
+
+// CheckFairness checks the fairness parameter γ and the fault threshold for n participants, and the zero γ refers to
+// DefaultFairnessGamma, the nil fault threshold refers to the max amount of byzantine nodes tolerated with γ. The γ
+// receive-order-fairness could only be achieved with n > 4f/(2γ-1).
+func CheckFairness(gamma float64, fault *int, n int) error {
+	if gamma != 0 && (gamma <= 0.5 || gamma > 1) {
+		return fmt.Errorf("invalid fairness parameter %f, expect (0.5, 1]", gamma)
+	}
+	if fault == nil {
+		return nil
+	}
+	if *fault < 0 || *fault >= n {
+		return fmt.Errorf("invalid fault threshold %d for %d participants", *fault, n)
+	}
+	if tolerance := CalculateFairnessFault(gamma, n); *fault > tolerance {
+		return fmt.Errorf("fault threshold %d exceeds the tolerance %d of fairness parameter %f for %d participants", *fault, tolerance, gamma, n)
+	}
+	return nil
+}
+
+// CalculateFairnessFault returns the max amount of byzantine nodes tolerated with fairness parameter γ,
+// which satisfies n > 4f/(2γ-1) and doesn't exceed the max amount of byzantine nodes.
+func CalculateFairnessFault(gamma float64, n int) int {
+	if gamma == 0 {
+		gamma = DefaultFairnessGamma
+	}
+	// the tolerance avoids the rounding error of float numbers.
+	fault := int(math.Ceil((2*gamma-1)*float64(n)/4-1e-9)) - 1
+	if max := CalculateFault(n); fault > max {
+		fault = max
+	}
+	if fault < 0 {
+		fault = 0
+	}
+	return fault
+}
+
+// CalculateStableThreshold returns the number of partial orders for a command to become stable with the fault threshold,
+// which is n-f. It would be clamped to the quorum of byzantine system, since the partial orders from the byzantine nodes
+// may never arrive, e.g. the fault threshold tolerated with γ is lower than the byzantine ones for a few participants.
+func CalculateStableThreshold(fault int, n int) int {
+	stable := n - fault
+	if quorum := CalculateQuorum(n); stable > quorum {
+		stable = quorum
+	}
+	return stable
+}
+
+// CalculateFairnessThreshold returns the number of partial orders to decide the precedence between two commands.
+func CalculateFairnessThreshold(gamma float64, n int) int {
+	if gamma == 0 {
+		gamma = DefaultFairnessGamma
+	}
+	// the tolerance avoids the rounding error of float numbers, e.g. 2/3 * 3.
+	return int(math.Ceil(gamma*float64(n) - 1e-9))
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// HashSHA256 is the default hash algorithm for digests.
+	HashSHA256 = "SHA256"
+
+	// HashBLAKE2b is the BLAKE2b hash algorithm with 256-bit output.
+	HashBLAKE2b = "BLAKE2b-256"
+
+	// HashSHA3 is the SHA3 hash algorithm with 256-bit output.
+	HashSHA3 = "SHA3-256"
+)
+
+// Hasher is used to generate the digests of messages, such as transactions, commands and pre-orders.
+type Hasher interface {
+	// Algorithm returns the name of hash algorithm.
+	Algorithm() string
+
+	// New returns a new hash instance.
+	New() hash.Hash
+}
+
+type hasherImpl struct {
+	algorithm string
+	newFunc   func() hash.Hash
+}
+
+func (h *hasherImpl) Algorithm() string {
+	return h.algorithm
+}
+
+func (h *hasherImpl) New() hash.Hash {
+	return h.newFunc()
+}
+
+// NewHasher returns the hasher for given algorithm, and a blank one refers to the default SHA256.
+func NewHasher(algorithm string) (Hasher, error) {
+	switch algorithm {
+	case "", HashSHA256:
+		return &hasherImpl{algorithm: HashSHA256, newFunc: sha256.New}, nil
+	case HashBLAKE2b:
+		return &hasherImpl{algorithm: HashBLAKE2b, newFunc: newBLAKE2b}, nil
+	case HashSHA3:
+		return &hasherImpl{algorithm: HashSHA3, newFunc: sha3.New256}, nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %s", algorithm)
+	}
+}
+
+func newBLAKE2b() hash.Hash {
+	// the error could only be returned for an illegal key, and we don't use a key here.
+	h, _ := blake2b.New256(nil)
+	return h
+}
+
+// DefaultHasher returns the hasher with the default SHA256 algorithm.
+func DefaultHasher() Hasher {
+	return &hasherImpl{algorithm: HashSHA256, newFunc: sha256.New}
+}
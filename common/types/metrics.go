@@ -28,6 +28,18 @@ type MetricsInfo struct {
 	//
 	GenLogPS float64
 
+	// RejectedPreOrders is the number of pre-orders we have refused to vote for with invalid timestamps.
+	RejectedPreOrders int
+
+	// RejectedCommands is the number of commands we have refused without a valid client signature.
+	RejectedCommands int
+
+	// StalledClients is the number of clients stalled by missing commands.
+	StalledClients int
+
+	// SkippedCommands is the number of missing commands we have skipped.
+	SkippedCommands int
+
 	//======================================= Executor Metrics ====================================================
 
 	// AveLogLatency indicates interval since generate partial order to commit partial order.
@@ -71,6 +83,12 @@ type MetricsInfo struct {
 	//
 	SuccessRates []float64
 
+	// CycleCount indicates the number of precedence cycles.
+	CycleCount int
+
+	// AveCycleSize indicates the average number of commands in one precedence cycle.
+	AveCycleSize float64
+
 	//======================================= Executor Timestamp-Based =================================================
 
 	// MSafeCommandCount indicates the number of command committed from safe path.
@@ -116,4 +134,50 @@ type MetricsInfo struct {
 
 	//
 	TASuccessRates []float64
+
+	//======================================= Executor Themis-Based ====================================================
+
+	// THSafeCommandCount indicates the number of command committed in the batches with a single command.
+	THSafeCommandCount int
+
+	// THRiskCommandCount indicates the number of command committed in the batches with cyclic orderings.
+	THRiskCommandCount int
+
+	// THFrontAttackFromRisk records the front attacked command requests from risk path.
+	THFrontAttackFromRisk int
+
+	// THFrontAttackFromSafe records the front attacked command requests from safe path.
+	THFrontAttackFromSafe int
+
+	// THFrontAttackIntervalRisk records the front attacked command requests of interval relationship from risk path.
+	THFrontAttackIntervalRisk int
+
+	// THFrontAttackIntervalSafe records the front attacked command requests of interval relationship from safe path.
+	THFrontAttackIntervalSafe int
+
+	//
+	THSuccessRates []float64
+
+	//======================================= Executor Aequitas-Based ==================================================
+
+	// AQSafeCommandCount indicates the number of command finalized in the batches with a single command.
+	AQSafeCommandCount int
+
+	// AQRiskCommandCount indicates the number of command finalized in the batches with undecided precedence or cycles.
+	AQRiskCommandCount int
+
+	// AQFrontAttackFromRisk records the front attacked command requests from risk path.
+	AQFrontAttackFromRisk int
+
+	// AQFrontAttackFromSafe records the front attacked command requests from safe path.
+	AQFrontAttackFromSafe int
+
+	// AQFrontAttackIntervalRisk records the front attacked command requests of interval relationship from risk path.
+	AQFrontAttackIntervalRisk int
+
+	// AQFrontAttackIntervalSafe records the front attacked command requests of interval relationship from safe path.
+	AQFrontAttackIntervalSafe int
+
+	//
+	AQSuccessRates []float64
 }
@@ -16,6 +16,9 @@ type OrderInfo struct {
 	// Command indicates the digest of command which current info is ordered for.
 	Command string
 
+	// Client indicates the client and the sequence number assigned by it for the command.
+	Client QueryIndex
+
 	// Timestamp indicates the time when current order is generated.
 	Timestamp int64
 
@@ -30,12 +33,17 @@ func NewOrderInfos(seqNo uint64, pOrder *protos.PartialOrder) ([]OrderInfo, uint
 
 	timestampList := pOrder.TimestampList()
 
+	clientList := pOrder.ClientList()
+
+	clientSeqList := pOrder.ClientSeqList()
+
 	var infos []OrderInfo
 
 	for index, command := range commandList {
 		timestamp := timestampList[index]
+		client := QueryIndex{Author: clientList[index], SeqNo: clientSeqList[index]}
 		seqNo++
-		info := OrderInfo{Author: author, Sequence: seqNo, Command: command, Timestamp: timestamp}
+		info := OrderInfo{Author: author, Sequence: seqNo, Command: command, Client: client, Timestamp: timestamp}
 		infos = append(infos, info)
 	}
 
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// OverloadBlock waits for room in a full queue, which is the default overload policy.
+	OverloadBlock = "block"
+
+	// OverloadDropOldest drops the oldest item in a full queue to make room for the new one, and ErrOverload is returned
+	// for the dropped item. At most one item would be dropped for each new one.
+	OverloadDropOldest = "drop-oldest"
+
+	// OverloadReject refuses the new item with ErrOverload once the queue is full.
+	OverloadReject = "reject"
+)
+
+// ErrOverload is returned once a bounded queue is full and the overload policy refuses to wait for room.
+var ErrOverload = errors.New("phalanx is overloaded")
+
+// ErrClosed is returned once phalanx has been closed while we are waiting for room in a full queue.
+var ErrClosed = errors.New("phalanx has been closed")
+
+// CheckOverloadPolicy checks if the policy is supported, and a blank one refers to OverloadBlock.
+func CheckOverloadPolicy(policy string) error {
+	switch policy {
+	case "", OverloadBlock, OverloadDropOldest, OverloadReject:
+		return nil
+	default:
+		return fmt.Errorf("unsupported overload policy %s", policy)
+	}
+}
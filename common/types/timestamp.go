@@ -0,0 +1,27 @@
+package types
+
+import (
+	"time"
+)
+
+// TimestampPolicy is used to validate the timestamps in pre-orders before we vote for them,
+// so that a byzantine replica couldn't backdate its timestamps to front-run the commands of others.
+type TimestampPolicy struct {
+	// Enabled indicates if we would like to validate the timestamps.
+	Enabled bool
+
+	// Monotonic indicates if the timestamps should be non-decreasing along the chain of replica.
+	Monotonic bool
+
+	// MaxSkew is the tolerated clock skew of the timestamps ahead of our local time, 0 means unlimited.
+	MaxSkew time.Duration
+
+	// MaxSeenDelay is the max interval from the timestamp of a command to the time we have received it,
+	// 0 means unlimited.
+	MaxSeenDelay time.Duration
+}
+
+// NewDefaultTimestampPolicy returns the timestamp policy with all the validations enabled.
+func NewDefaultTimestampPolicy() TimestampPolicy {
+	return TimestampPolicy{Enabled: true, Monotonic: true, MaxSkew: DefaultMaxClockSkew, MaxSeenDelay: DefaultMaxSeenDelay}
+}
@@ -5,14 +5,12 @@ import (
 	"time"
 
 	"github.com/Grivn/phalanx/common/protos"
-
-	"github.com/gogo/protobuf/proto"
 )
 
 //=================================== Command Generator =======================================
 
 // GenerateCommand generates command with given transaction list.
-func GenerateCommand(author uint64, seqNo uint64, txs []*protos.Transaction) *protos.Command {
+func GenerateCommand(hasher Hasher, author uint64, seqNo uint64, txs []*protos.Transaction) *protos.Command {
 	var hashList []string
 	for _, tx := range txs {
 		hashList = append(hashList, tx.Hash)
@@ -22,33 +20,33 @@ func GenerateCommand(author uint64, seqNo uint64, txs []*protos.Transaction) *pr
 		Sequence: seqNo,
 		HashList: hashList,
 	}
-	payload, err := proto.Marshal(command)
+	digest, err := CalculateCommandDigest(hasher, command)
 	if err != nil {
 		return nil
 	}
-	command.Digest = CalculatePayloadHash(payload, 0)
+	command.Digest = digest
 	command.Content = txs
 	command.GTime = time.Now().UnixNano()
 	return command
 }
 
-func GenerateRandCommand(author uint64, seqNo uint64, count, size int) *protos.Command {
+func GenerateRandCommand(hasher Hasher, author uint64, seqNo uint64, count, size int) *protos.Command {
 	tList := make([]*protos.Transaction, count)
 	hList := make([]string, count)
 
 	for i:=0; i<count; i++ {
-		tx := GenerateRandTransaction(size)
+		tx := GenerateRandTransaction(hasher, size)
 
 		tList[i] = tx
 		hList[i] = tx.Hash
 	}
 
 	command := &protos.Command{Author: author, Sequence: seqNo, HashList: hList}
-	payload, err := proto.Marshal(command)
+	digest, err := CalculateCommandDigest(hasher, command)
 	if err != nil {
 		panic(err)
 	}
-	command.Digest = CalculatePayloadHash(payload, 0)
+	command.Digest = digest
 	command.Content = tList
 
 	return command
@@ -56,16 +54,18 @@ func GenerateRandCommand(author uint64, seqNo uint64, count, size int) *protos.C
 
 //==================================== Transaction Generator ====================================
 
-func GenerateRandTransaction(size int) *protos.Transaction {
+func GenerateRandTransaction(hasher Hasher, size int) *protos.Transaction {
 	payload := make([]byte, size)
 	rand.Read(payload)
-	return GenerateTransaction(payload)
+	return GenerateTransaction(hasher, payload)
 }
 
-func GenerateTransaction(payload []byte) *protos.Transaction {
+func GenerateTransaction(hasher Hasher, payload []byte) *protos.Transaction {
+	// the hash should be calculated with the same timestamp carried by transaction, so that others could verify it.
+	timestamp := time.Now().UnixNano()
 	return &protos.Transaction{
-		Hash:      CalculatePayloadHash(payload, time.Now().UnixNano()),
+		Hash:      CalculatePayloadHash(hasher, payload, timestamp),
 		Payload:   payload,
-		Timestamp: time.Now().UnixNano(),
+		Timestamp: timestamp,
 	}
 }
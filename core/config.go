@@ -3,6 +3,7 @@ package phalanx
 import (
 	"time"
 
+	"github.com/Grivn/phalanx/common/types"
 	"github.com/Grivn/phalanx/external"
 )
 
@@ -13,17 +14,39 @@ type Config struct {
 	Snapping    bool
 	OpenLatency int
 	Duration    time.Duration
+	BatchSize   int
+	BatchMode   string
 	Interval    int
 	CDuration   time.Duration
 	N           int
 	Multi       int
 	LogCount    int
 	MemSize     int
+	QueueSize   int
+	StreamSize  int
+	Strategy    string
+	Shadows     []string
+	Gamma       float64
+	Fault       *int
+	Overload    string
 	CommandSize int
 	Selected    uint64
+	WALPath     string
+	Hasher      string
+	Window      int
+	Timestamp   types.TimestampPolicy
+	CmdLimit    types.CommandLimit
+	Workers     int
+	CacheSize   int
 	PrivateKey  external.PrivateKey
 	PublicKeys  map[uint64]external.PublicKey
+	KeyDecoder  external.PublicKeyDecoder
+	ClientKeys  map[uint64]external.PublicKey
+	Signers     map[uint64]external.PrivateKey
+	Aggregator  external.SignatureAggregator
 	Exec        external.ExecutionService
+	Misbehavior external.MisbehaviorService
+	Clients     external.ClientService
 	Network     external.NetworkService
 	Logger      external.Logger
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/Grivn/phalanx/executor/finality"
 	"github.com/Grivn/phalanx/external"
 	"github.com/Grivn/phalanx/metapool"
+	"github.com/Grivn/phalanx/metapool/batch"
 	"github.com/Grivn/phalanx/metapool/crypto"
 	"github.com/Grivn/phalanx/metrics"
 	"github.com/Grivn/phalanx/receiver"
@@ -37,15 +38,44 @@ type phalanxImpl struct {
 	logger external.Logger
 }
 
-func NewPhalanxProvider(conf Config) *phalanxImpl {
+func NewPhalanxProvider(conf Config) (*phalanxImpl, error) {
 	// todo read crypto key pairs from config files.
 	// initiate key pairs.
 
 	// initiate phalanx logger.
 	mLogs, err := newPLogger(conf.Logger, true, conf.Author)
 	if err != nil {
-		conf.Logger.Errorf("Generate Phalanx Logger Failed: %s", err)
-		return nil
+		return nil, fmt.Errorf("generate phalanx logger failed: %s", err)
+	}
+
+	// initiate the hasher for digests, which should be the same one among participants.
+	hasher, err := types.NewHasher(conf.Hasher)
+	if err != nil {
+		return nil, fmt.Errorf("generate phalanx hasher failed: %s", err)
+	}
+
+	// initiate the batching policy for pre-orders.
+	batchPolicy, err := batch.NewBatchPolicy(conf.BatchMode, conf.BatchSize, conf.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("generate phalanx batch policy failed: %s", err)
+	}
+
+	// check the overload policy for the queues from receiver to meta pool.
+	if err := types.CheckOverloadPolicy(conf.Overload); err != nil {
+		return nil, fmt.Errorf("check phalanx overload policy failed: %s", err)
+	}
+
+	// check the fairness parameters if the aequitas-based ordering has been selected.
+	aequitas := conf.Strategy == types.StrategyAequitas
+	for _, name := range conf.Shadows {
+		if name == types.StrategyAequitas {
+			aequitas = true
+		}
+	}
+	if aequitas {
+		if err := types.CheckFairness(conf.Gamma, conf.Fault, conf.N); err != nil {
+			return nil, fmt.Errorf("check phalanx fairness failed: %s", err)
+		}
 	}
 
 	// create metrics.
@@ -57,37 +87,77 @@ func NewPhalanxProvider(conf Config) *phalanxImpl {
 		Multi:       conf.Multi,
 		CommandSize: conf.CommandSize,
 		MemSize:     conf.MemSize,
+		QueueSize:   conf.QueueSize,
+		Overload:    conf.Overload,
 		Selected:    conf.Selected,
+		Hasher:      hasher,
+		Signers:     conf.Signers,
 		Sender:      conf.Network,
 		Logger:      mLogs.txManagerLog,
 	}
+
+	// the commands generated by receiver should be signed once the client authentication has been enabled,
+	// otherwise, they would be rejected by meta pool.
+	if len(conf.ClientKeys) > 0 {
+		if err := receiver.CheckSigners(txConf); err != nil {
+			return nil, fmt.Errorf("check phalanx client signers failed: %s", err)
+		}
+	}
 	proposer := receiver.NewTxManager(txConf)
 
 	// initiate meta pool.
 	mpConf := metapool.Config{
-		Author:   conf.Author,
-		Byz:      conf.Byz,
-		Snapping: conf.Snapping,
-		N:        conf.N,
-		Multi:    conf.Multi,
-		Crypto:   crypto.NewCrypto(conf.PrivateKey, conf.PublicKeys),
-		Sender:   conf.Network,
-		Logger:   mLogs.metaPoolLog,
-		Metrics:  pMetrics.MetaPoolMetrics,
+		Author:       conf.Author,
+		Byz:          conf.Byz,
+		Snapping:     conf.Snapping,
+		N:            conf.N,
+		Multi:        conf.Multi,
+		Window:       conf.Window,
+		Readers:      1,
+		QueueSize:    conf.QueueSize,
+		Overload:     conf.Overload,
+		Batch:        batchPolicy,
+		FetchTimeout: types.DefaultFetchTimeout,
+		VoteTimeout:  types.DefaultVoteTimeout,
+		GapTimeout:   types.DefaultGapTimeout,
+		WALPath:      conf.WALPath,
+		Timestamp:    conf.Timestamp,
+		CommandLimit: conf.CmdLimit,
+		Hasher:       hasher,
+		Crypto:       crypto.NewCrypto(conf.PrivateKey, conf.PublicKeys, conf.Aggregator, hasher, conf.Workers, conf.CacheSize),
+		KeyDecoder:   conf.KeyDecoder,
+		ClientKeys:   conf.ClientKeys,
+		Misbehavior:  conf.Misbehavior,
+		Clients:      conf.Clients,
+		Sender:       conf.Network,
+		Logger:       mLogs.metaPoolLog,
+		Metrics:      pMetrics.MetaPoolMetrics,
+	}
+	mPool, err := metapool.NewMetaPool(mpConf)
+	if err != nil {
+		return nil, fmt.Errorf("generate phalanx meta pool failed: %s", err)
 	}
-	mPool := metapool.NewMetaPool(mpConf)
 
 	// initiate executor.
 	exeConf := finality.Config{
-		Author:  conf.Author,
-		OLeader: conf.OLeader,
-		N:       conf.N,
-		Pool:    mPool,
-		Exec:    conf.Exec,
-		Logger:  mLogs.executorLog,
-		Metrics: pMetrics,
+		Author:     conf.Author,
+		OLeader:    conf.OLeader,
+		N:          conf.N,
+		Checkpoint: types.DefaultCheckpointInterval,
+		StreamSize: conf.StreamSize,
+		Strategy:   conf.Strategy,
+		Shadows:    conf.Shadows,
+		Gamma:      conf.Gamma,
+		Fault:      conf.Fault,
+		Pool:       mPool,
+		Exec:       conf.Exec,
+		Logger:     mLogs.executorLog,
+		Metrics:    pMetrics,
+	}
+	executor, err := finality.NewFinality(exeConf)
+	if err != nil {
+		return nil, fmt.Errorf("generate phalanx executor failed: %s", err)
 	}
-	executor := finality.NewFinality(exeConf)
 
 	return &phalanxImpl{
 		author:   conf.Author,
@@ -96,7 +166,7 @@ func NewPhalanxProvider(conf Config) *phalanxImpl {
 		executor: executor,
 		logger:   conf.Logger,
 		metrics:  pMetrics,
-	}
+	}, nil
 }
 
 func (phi *phalanxImpl) Run() {
@@ -107,16 +177,17 @@ func (phi *phalanxImpl) Run() {
 
 func (phi *phalanxImpl) Quit() {
 	phi.metaPool.Quit()
+	phi.executor.Quit()
 }
 
 // ReceiveTransaction is used to process transaction we have received.
-func (phi *phalanxImpl) ReceiveTransaction(tx *protos.Transaction) {
-	phi.proposer.ProcessTransaction(tx)
+func (phi *phalanxImpl) ReceiveTransaction(tx *protos.Transaction) error {
+	return phi.proposer.ProcessTransaction(tx)
 }
 
 // ReceiveCommand is used to process the commands from clients.
-func (phi *phalanxImpl) ReceiveCommand(command *protos.Command) {
-	phi.metaPool.ProcessCommand(command)
+func (phi *phalanxImpl) ReceiveCommand(command *protos.Command) error {
+	return phi.metaPool.ProcessCommand(command)
 }
 
 // ReceiveConsensusMessage is used process the consensus messages from phalanx replica.
@@ -146,6 +217,46 @@ func (phi *phalanxImpl) ReceiveConsensusMessage(message *protos.ConsensusMessage
 		if err := phi.metaPool.ProcessVote(vote); err != nil {
 			phi.logger.Errorf("[%d] failed process vote, error msg: %s", phi.author, err)
 		}
+	case protos.MessageType_FETCH_PARTIAL:
+		fetch := &protos.FetchPartial{}
+		if err := proto.Unmarshal(message.Payload, fetch); err != nil {
+			return fmt.Errorf("unmarshal error: %s", err)
+		}
+		if err := phi.metaPool.ProcessFetchPartial(fetch); err != nil {
+			phi.logger.Errorf("[%d] failed process fetch-partial, error msg: %s", phi.author, err)
+		}
+	case protos.MessageType_RETURN_PARTIAL:
+		pOrder := &protos.PartialOrder{}
+		if err := proto.Unmarshal(message.Payload, pOrder); err != nil {
+			return fmt.Errorf("unmarshal error: %s", err)
+		}
+		if err := phi.metaPool.ProcessReturnPartial(pOrder); err != nil {
+			phi.logger.Errorf("[%d] failed process returned partial-order, error msg: %s", phi.author, err)
+		}
+	case protos.MessageType_FETCH_COMMAND:
+		fetch := &protos.FetchCommand{}
+		if err := proto.Unmarshal(message.Payload, fetch); err != nil {
+			return fmt.Errorf("unmarshal error: %s", err)
+		}
+		if err := phi.metaPool.ProcessFetchCommand(fetch); err != nil {
+			phi.logger.Errorf("[%d] failed process fetch-command, error msg: %s", phi.author, err)
+		}
+	case protos.MessageType_RETURN_COMMAND:
+		command := &protos.Command{}
+		if err := proto.Unmarshal(message.Payload, command); err != nil {
+			return fmt.Errorf("unmarshal error: %s", err)
+		}
+		if err := phi.metaPool.ProcessReturnCommand(command); err != nil {
+			phi.logger.Errorf("[%d] failed process returned command, error msg: %s", phi.author, err)
+		}
+	case protos.MessageType_MISBEHAVIOR:
+		proof := &protos.MisbehaviorProof{}
+		if err := proto.Unmarshal(message.Payload, proof); err != nil {
+			return fmt.Errorf("unmarshal error: %s", err)
+		}
+		if err := phi.metaPool.ProcessMisbehavior(proof); err != nil {
+			phi.logger.Errorf("[%d] failed process misbehavior proof, error msg: %s", phi.author, err)
+		}
 	}
 	return nil
 }
@@ -165,9 +276,25 @@ func (phi *phalanxImpl) CommitProposal(pBatch *protos.PartialOrderBatch) error {
 		return err
 	}
 	phi.executor.CommitStream(qStream)
+
+	if pBatch.Reconfiguration != nil {
+		// the membership change takes effect after the query stream of current batch.
+		phi.executor.Reconfigure(pBatch.Reconfiguration)
+	}
 	return nil
 }
 
+// ProposeReconfiguration is used to propose a membership change with the next phalanx proposal,
+// which would take effect once the proposal has been committed.
+func (phi *phalanxImpl) ProposeReconfiguration(reconf *protos.Reconfiguration) error {
+	return phi.metaPool.ProposeReconfiguration(reconf)
+}
+
+// SignReconfiguration generates the signature of current node to authorize the membership change.
+func (phi *phalanxImpl) SignReconfiguration(reconf *protos.Reconfiguration) (*protos.Certification, error) {
+	return phi.metaPool.SignReconfiguration(reconf)
+}
+
 // QueryMetrics returns the metrics info of phalanx.
 func (phi *phalanxImpl) QueryMetrics() types.MetricsInfo {
 	return phi.metrics.QueryMetrics()
@@ -12,6 +12,7 @@ type Provider interface {
 	Communicator
 	Generator
 	Executor
+	Reconfigurator
 
 	// QueryMetrics returns the metrics info of phalanx.
 	QueryMetrics() types.MetricsInfo
@@ -26,13 +27,15 @@ type Runner interface {
 // Receiver is used to receive transactions and push them into phalanx memory pool.
 type Receiver interface {
 	// ReceiveTransaction is used to process transaction we have received.
-	ReceiveTransaction(tx *protos.Transaction)
+	// It returns types.ErrOverload once the transaction has been refused with overload policy.
+	ReceiveTransaction(tx *protos.Transaction) error
 }
 
 // Communicator is used to process messages from network.
 type Communicator interface {
 	// ReceiveCommand is used to process the commands from clients.
-	ReceiveCommand(command *protos.Command)
+	// It returns types.ErrOverload once the command has been refused with overload policy.
+	ReceiveCommand(command *protos.Command) error
 
 	// ReceiveConsensusMessage is used process the consensus messages from phalanx replica.
 	ReceiveConsensusMessage(message *protos.ConsensusMessage) error
@@ -49,3 +52,14 @@ type Executor interface {
 	// CommitProposal is used to commit the phalanx proposal which has been verified with consensus.
 	CommitProposal(pBatch *protos.PartialOrderBatch) error
 }
+
+// Reconfigurator is used to change the membership of phalanx cluster.
+type Reconfigurator interface {
+	// ProposeReconfiguration is used to propose a membership change with the next phalanx proposal,
+	// which would take effect once the proposal has been committed. The reconfiguration should carry the
+	// quorum-cert of the participants in current epoch, which is aggregated with SignReconfiguration.
+	ProposeReconfiguration(reconf *protos.Reconfiguration) error
+
+	// SignReconfiguration generates the signature of current node to authorize the membership change.
+	SignReconfiguration(reconf *protos.Reconfiguration) (*protos.Certification, error)
+}
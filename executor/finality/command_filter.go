@@ -0,0 +1,62 @@
+package finality
+
+import (
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
+)
+
+// commandFilter is used to make sure only one command would be executed for each client and sequence number.
+// An equivocating client may issue conflicting commands with the same sequence number, and both of them could
+// be committed by phalanx, we would only execute the first committed one, which is deterministic among participants.
+type commandFilter struct {
+	// executed records the digests of the commands executed for each client and sequence number.
+	executed map[types.QueryIndex]string
+
+	// watermarks are the sequence numbers of clients, which the commands up to have all been executed. The commands
+	// of one client may be executed out of the order of sequence numbers, e.g. with the median timestamps, so that the
+	// watermark would only be advanced over a contiguous run of executed commands.
+	watermarks map[uint64]uint64
+}
+
+func newCommandFilter() *commandFilter {
+	return &commandFilter{
+		executed:   make(map[types.QueryIndex]string),
+		watermarks: make(map[uint64]uint64),
+	}
+}
+
+// admit returns if the command could be executed, and it would be recorded as the executed one for its sequence number.
+func (cf *commandFilter) admit(command *protos.Command) bool {
+	if command.Sequence <= cf.watermarks[command.Author] {
+		// there is a command executed for each sequence number below watermark.
+		return false
+	}
+
+	idx := types.QueryIndex{Author: command.Author, SeqNo: command.Sequence}
+	if digest, ok := cf.executed[idx]; ok {
+		return digest == command.Digest
+	}
+
+	cf.executed[idx] = command.Digest
+	return true
+}
+
+// checkpoint garbage collects the records of the contiguous executed commands for each client, and the commands with
+// these sequence numbers would be rejected with watermarks.
+func (cf *commandFilter) checkpoint() {
+	for idx := range cf.executed {
+		watermark := cf.watermarks[idx.Author]
+		if idx.SeqNo != watermark+1 {
+			continue
+		}
+		for {
+			next := types.QueryIndex{Author: idx.Author, SeqNo: watermark + 1}
+			if _, ok := cf.executed[next]; !ok {
+				break
+			}
+			delete(cf.executed, next)
+			watermark++
+		}
+		cf.watermarks[idx.Author] = watermark
+	}
+}
@@ -0,0 +1,37 @@
+package finality
+
+import (
+	"testing"
+
+	"github.com/Grivn/phalanx/common/protos"
+)
+
+func TestCommandFilterOutOfOrder(t *testing.T) {
+	cf := newCommandFilter()
+
+	// the commands of one client may be executed out of the order of sequence numbers.
+	for _, command := range []*protos.Command{{Author: 1, Sequence: 2, Digest: "b"}, {Author: 1, Sequence: 1, Digest: "a"}} {
+		if !cf.admit(command) {
+			t.Fatalf("expect command %s admitted", command.Digest)
+		}
+	}
+
+	// the conflicting command with the same sequence number should be rejected.
+	if cf.admit(&protos.Command{Author: 1, Sequence: 1, Digest: "c"}) {
+		t.Fatalf("expect conflicting command rejected")
+	}
+
+	cf.checkpoint()
+	if watermark := cf.watermarks[1]; watermark != 2 {
+		t.Fatalf("expect watermark 2, received %d", watermark)
+	}
+	if len(cf.executed) != 0 {
+		t.Fatalf("expect executed records garbage collected, received %d", len(cf.executed))
+	}
+	if cf.admit(&protos.Command{Author: 1, Sequence: 2, Digest: "d"}) {
+		t.Fatalf("expect conflicting command below watermark rejected")
+	}
+	if !cf.admit(&protos.Command{Author: 1, Sequence: 4, Digest: "e"}) {
+		t.Fatalf("expect command above the gap admitted")
+	}
+}
@@ -7,11 +7,17 @@ import (
 )
 
 type Config struct {
-	Author  uint64
-	OLeader uint64
-	N       int
-	Pool    api.MetaPool
-	Exec    external.ExecutionService
-	Logger  external.Logger
-	Metrics *metrics.Metrics
+	Author     uint64
+	OLeader    uint64
+	N          int
+	Checkpoint uint64
+	StreamSize int
+	Strategy   string
+	Shadows    []string
+	Gamma      float64
+	Fault      *int
+	Pool       api.MetaPool
+	Exec       external.ExecutionService
+	Logger     external.Logger
+	Metrics    *metrics.Metrics
 }
@@ -1,11 +1,13 @@
 package finality
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/Grivn/phalanx/common/api"
+	"github.com/Grivn/phalanx/common/protos"
 	"github.com/Grivn/phalanx/common/types"
 	"github.com/Grivn/phalanx/external"
 	"github.com/Grivn/phalanx/metrics"
@@ -20,6 +22,9 @@ type finalityImpl struct {
 	// author indicates the identifier of current node.
 	author uint64
 
+	// epoch indicates the epoch number of current membership.
+	epoch uint64
+
 	//========================= concurrency committed query stream processor =============================
 
 	// cache is used to process the query streams commit into finality module.
@@ -33,20 +38,34 @@ type finalityImpl struct {
 	// orderSeq tracks the real committed partial order sequence number.
 	orderSeq map[uint64]uint64
 
-	// phalanxAnchor is used to generate blocks with phalanx anchor-based ordering rule.
-	phalanxAnchor *phalanxAnchorBasedOrdering
+	//=============================== checkpoint management ===========================================
+
+	// interval is the number of committed query streams between two checkpoints, 0 means no checkpoint.
+	interval uint64
+
+	// streamNo is the number of query streams we have committed.
+	streamNo uint64
 
-	// timestampAnchor is used to generate blocks with timestamp anchor-based ordering rule.
-	timestampAnchor *timestampAnchorBasedOrdering
+	// watermarks track the highest committed partial order sequence number for each participant.
+	watermarks map[uint64]uint64
 
-	// timestampBased is used to generate blocks with timestamp-based ordering rule.
-	timestampBased *timestampBasedOrdering
+	//============================ ordering strategies ========================================
+
+	// strategy is used to generate and execute blocks with the selected ordering rule.
+	strategy OrderingStrategy
+
+	// shadow is used to process the order streams with the strategies which only record metrics,
+	// and it is nil if there isn't any shadow strategy.
+	shadow *shadowRunner
 
 	//============================= internal interfaces =========================================
 
 	// reader is used to read partial orders from meta pool tracker.
 	reader api.MetaReader
 
+	// pool is used to notify meta pool the stable checkpoint.
+	pool api.MetaCheckpoint
+
 	// metrics is used to record the metric of current node's executor.
 	metrics *metrics.ExecutorMetrics
 
@@ -56,27 +75,63 @@ type finalityImpl struct {
 	logger external.Logger
 }
 
-func NewFinality(conf Config) *finalityImpl {
+func NewFinality(conf Config) (*finalityImpl, error) {
 	author := conf.Author
 	orderSeq := make(map[uint64]uint64)
+	watermarks := make(map[uint64]uint64)
 
-	for i := 0; i < conf.N; i++ {
-		id := uint64(i + 1)
+	// the membership may have been changed with the recovery of meta pool.
+	epoch, members := conf.Pool.Members()
+	for _, id := range members {
 		orderSeq[id] = uint64(0)
+		watermarks[id] = uint64(0)
 	}
 
-	return &finalityImpl{
-		author:          author,
-		cache:           newStreamCache(),
-		closeC:          make(chan bool),
-		orderSeq:        orderSeq,
-		phalanxAnchor:   newPhalanxAnchorBasedOrdering(conf),
-		timestampAnchor: newTimestampAnchorBasedOrdering(conf),
-		timestampBased:  newTimestampBasedOrdering(conf),
-		reader:          conf.Pool,
-		metrics:         conf.Metrics.ExecutorMetrics,
-		logger:          conf.Logger,
+	strategy, err := NewOrderingStrategy(conf.Strategy, conf, members, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// each strategy reads the committed commands from meta pool, so that we shouldn't run one of them twice.
+	selected := map[string]bool{conf.Strategy: true}
+	if conf.Strategy == "" {
+		selected[types.StrategyPhalanxAnchor] = true
+	}
+	var shadows []OrderingStrategy
+	shadowConf := conf
+	shadowConf.Pool = shadowReader{MetaPool: conf.Pool}
+	for _, name := range conf.Shadows {
+		if selected[name] {
+			return nil, fmt.Errorf("duplicated ordering strategy %s", name)
+		}
+		selected[name] = true
+
+		shadow, err := NewOrderingStrategy(name, shadowConf, members, true)
+		if err != nil {
+			return nil, err
+		}
+		shadows = append(shadows, shadow)
+	}
+	var runner *shadowRunner
+	if len(shadows) > 0 {
+		runner = newShadowRunner(shadows, conf.StreamSize, conf.Pool)
 	}
+
+	return &finalityImpl{
+		author:     author,
+		epoch:      epoch,
+		cache:      newStreamCache(conf.StreamSize),
+		closeC:     make(chan bool),
+		orderSeq:   orderSeq,
+		interval:   conf.Checkpoint,
+		watermarks: watermarks,
+		strategy:   strategy,
+		shadow:     runner,
+		reader:     conf.Pool,
+		pool:       conf.Pool,
+		metrics:    conf.Metrics.ExecutorMetrics,
+		logger:     conf.Logger,
+	}, nil
 }
 
 // CommitStream is used to commit the partial order stream.
@@ -84,13 +139,24 @@ func (ei *finalityImpl) CommitStream(qStream types.QueryStream) {
 	ei.cache.append(qStream)
 }
 
+// Reconfigure is used to commit the membership change, which takes effect after the streams committed before it.
+func (ei *finalityImpl) Reconfigure(reconf *protos.Reconfiguration) {
+	ei.cache.appendReconfiguration(reconf)
+}
+
 func (ei *finalityImpl) Run() {
+	if ei.shadow != nil {
+		go ei.shadow.run()
+	}
+
 	for {
 		select {
 		case <-ei.closeC:
 			return
 		default:
-			ei.processStreamList()
+			if !ei.processStreamList() {
+				return
+			}
 		}
 	}
 }
@@ -100,12 +166,49 @@ func (ei *finalityImpl) Quit() {
 	case <-ei.closeC:
 	default:
 		close(ei.closeC)
+		ei.cache.close()
+		if ei.shadow != nil {
+			ei.shadow.quit()
+		}
+	}
+}
+
+// processStreamList blocks until there is a committed query stream, and it returns false once the cache has been closed.
+func (ei *finalityImpl) processStreamList() bool {
+	switch item := ei.cache.front().(type) {
+	case types.QueryStream:
+		ei.commitStream(item)
+	case *protos.Reconfiguration:
+		ei.reconfigure(item)
+	default:
+		return false
 	}
+	return true
 }
 
-func (ei *finalityImpl) processStreamList() {
-	qStream := ei.cache.front()
-	ei.commitStream(qStream)
+// reconfigure is used to change the membership for each ordering strategy.
+func (ei *finalityImpl) reconfigure(reconf *protos.Reconfiguration) {
+	if reconf.Epoch != ei.epoch+1 {
+		ei.logger.Debugf("[%d] ignore reconfiguration %s, current epoch %d", ei.author, reconf.Format(), ei.epoch)
+		return
+	}
+
+	members := reconf.Members()
+	orderSeq := make(map[uint64]uint64, len(members))
+	watermarks := make(map[uint64]uint64, len(members))
+	for _, id := range members {
+		orderSeq[id] = ei.orderSeq[id]
+		watermarks[id] = ei.watermarks[id]
+	}
+	ei.orderSeq = orderSeq
+	ei.watermarks = watermarks
+	ei.epoch = reconf.Epoch
+
+	ei.strategy.Reconfigure(members)
+	if ei.shadow != nil {
+		ei.shadow.reconfigure(members)
+	}
+	ei.logger.Infof("[%d] reconfigured to epoch %d, members %v", ei.author, ei.epoch, members)
 }
 
 func (ei *finalityImpl) commitStream(qStream types.QueryStream) {
@@ -119,6 +222,10 @@ func (ei *finalityImpl) commitStream(qStream types.QueryStream) {
 
 	var oStream types.OrderStream
 	partials := ei.reader.ReadPartials(qStream)
+	if partials == nil {
+		// meta pool has been closed.
+		return
+	}
 	for _, pOrder := range partials {
 		// commit metrics.
 		ei.metrics.CommitPartialOrder(pOrder)
@@ -134,10 +241,41 @@ func (ei *finalityImpl) commitStream(qStream types.QueryStream) {
 	sort.Sort(oStream) // sort the command infos according to generator id and sequence number.
 	ei.logger.Debugf("[%d] commit order info stream len %d: %v", ei.author, len(oStream), oStream)
 
-	ei.phalanxAnchor.commitOrderStream(oStream)
-	ei.timestampBased.commitOrderStream(oStream)
-	ei.timestampAnchor.commitOrderStream(oStream)
+	ei.strategy.CommitOrderStream(oStream)
+	if ei.shadow != nil {
+		ei.shadow.commitOrderStream(oStream)
+	}
 
 	// record metrics.
 	ei.metrics.CommitStream(start)
+
+	// update the watermarks and try to generate a checkpoint.
+	for _, qIndex := range qStream {
+		if qIndex.SeqNo > ei.watermarks[qIndex.Author] {
+			ei.watermarks[qIndex.Author] = qIndex.SeqNo
+		}
+	}
+	ei.streamNo++
+	if ei.interval != 0 && ei.streamNo%ei.interval == 0 {
+		ei.checkpoint()
+	}
+}
+
+// checkpoint is used to generate a stable checkpoint with the committed query streams,
+// and then garbage collect the states below the previous one.
+func (ei *finalityImpl) checkpoint() {
+	watermarks := make(map[uint64]uint64, len(ei.watermarks))
+	for id, watermark := range ei.watermarks {
+		watermarks[id] = watermark
+	}
+	checkpoint := types.Checkpoint{Sequence: ei.streamNo, Watermarks: watermarks}
+	ei.logger.Infof("[%d] generate checkpoint %d, watermarks %v", ei.author, checkpoint.Sequence, checkpoint.Watermarks)
+
+	ei.strategy.Checkpoint()
+	if ei.shadow != nil {
+		// the commands would be garbage collected with the checkpoint, which should wait for the shadow strategies.
+		ei.shadow.checkpoint(checkpoint)
+		return
+	}
+	ei.pool.Checkpoint(checkpoint)
 }
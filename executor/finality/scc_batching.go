@@ -0,0 +1,391 @@
+package finality
+
+import (
+	"sort"
+
+	"github.com/Grivn/phalanx/common/api"
+	"github.com/Grivn/phalanx/common/types"
+	"github.com/Grivn/phalanx/executor/recorder"
+	"github.com/Grivn/phalanx/external"
+	"github.com/Grivn/phalanx/metrics"
+)
+
+// edgeRule decides the edges between two pending commands with the number of replicas which have ordered a before b,
+// and the ones which have ordered b before a. It returns if there is an edge from a to b, and from b to a.
+type edgeRule func(a, b *types.CommandInfo, ab, ba int) (bool, bool)
+
+// sccOrdering generates blocks with the batches of pending commands, which is shared by the batch-based fairness rules.
+//
+// The committed partial orders of each replica are regarded as its local ordering, and the edges between pending
+// commands are decided with the edge rule. The strongly connected components of the ordering graph are the batches
+// without a fair order among the commands inside, and they are committed in topological order once each command
+// inside has been ordered by stable replicas, the commands in one batch are sorted with trusted timestamp.
+type sccOrdering struct {
+	//============================ basic information =============================================
+
+	// author indicates the identifier of current node.
+	author uint64
+
+	// seqNo indicates the order of inner blocks.
+	seqNo uint64
+
+	// frontNo is used to track the sequence number for committed batches.
+	frontNo uint64
+
+	// oneCorrect indicates there is at least one correct node for bft.
+	oneCorrect int
+
+	// stable is the number of partial orders for each command in a batch before we commit it,
+	// so that the later commands couldn't be ordered before them.
+	stable int
+
+	// shadow indicates current strategy only records metrics, instead of executing blocks.
+	shadow bool
+
+	// graph is used to maintain the ordering graph of pending commands.
+	graph *orderGraph
+
+	//======================================= essential tools ===============================================
+
+	// cRecorder is used to record the command info.
+	cRecorder api.CommandRecorder
+
+	// filter is used to skip the conflicting commands issued by equivocating clients.
+	filter *commandFilter
+
+	// reload is used to notify client instance the committed sequence number.
+	reload api.MetaCommitter
+
+	// reader is used to read raw commands from meta pool.
+	reader api.MetaReader
+
+	// exec is used to execute the block.
+	exec external.ExecutionService
+
+	// metrics is used to record the metric of current ordering strategy.
+	metrics *metrics.ManipulationMetrics
+
+	// logger is used to print logs.
+	logger external.Logger
+}
+
+func newSCCOrdering(conf Config, members []uint64, shadow bool, rule edgeRule, metrics *metrics.ManipulationMetrics) *sccOrdering {
+	return &sccOrdering{
+		author:    conf.Author,
+		shadow:    shadow,
+		graph:     newOrderGraph(members, rule),
+		cRecorder: recorder.NewCommandRecorder(conf.Author, members, conf.Logger),
+		filter:    newCommandFilter(),
+		reader:    conf.Pool,
+		reload:    conf.Pool,
+		exec:      conf.Exec,
+		metrics:   metrics,
+		logger:    conf.Logger,
+	}
+}
+
+// reconfigure is used to update the thresholds and local orderings for the new membership.
+func (so *sccOrdering) reconfigure(members []uint64, oneCorrect int, stable int) {
+	so.oneCorrect = oneCorrect
+	so.stable = stable
+	so.graph.reconfigure(members)
+	so.cRecorder.Reconfigure(members)
+}
+
+// Checkpoint is used to garbage collect the command infos and executed commands before previous checkpoint.
+func (so *sccOrdering) Checkpoint() {
+	so.cRecorder.Checkpoint()
+	so.filter.checkpoint()
+}
+
+func (so *sccOrdering) CommitOrderStream(oStream types.OrderStream) {
+	if len(oStream) == 0 {
+		return
+	}
+
+	updated := false // if we have updated the pending commands.
+	for _, oInfo := range oStream {
+		if so.collectPartials(oInfo) {
+			updated = true
+		}
+	}
+
+	if updated {
+		// if the pending commands have been updated, try to commit the batches.
+		so.processPartialOrder()
+	}
+}
+
+func (so *sccOrdering) collectPartials(oInfo types.OrderInfo) bool {
+	// find the digest for current command the partial order refers to.
+	commandD := oInfo.Command
+
+	// check if current command has been committed or not.
+	if so.cRecorder.IsExecuted(oInfo) {
+		so.logger.Debugf("[%d] committed command %s, ignore it", so.author, commandD)
+		return false
+	}
+
+	// read command info from command cRecorder.
+	info := so.cRecorder.ReadCommandInfo(commandD)
+	info.OrderAppend(oInfo)
+	so.graph.update(info)
+	return true
+}
+
+// processPartialOrder is used to commit the batches in the front of topological order.
+func (so *sccOrdering) processPartialOrder() {
+	for _, batch := range so.graph.batches() {
+		for _, info := range batch {
+			if info.OrderCount() < so.stable {
+				// the edges of current batch may still be changed.
+				return
+			}
+		}
+
+		blocks := so.generateBlocks(batch)
+		if blocks == nil {
+			// meta pool has been closed.
+			return
+		}
+
+		// commit blocks.
+		so.logger.Debugf("[%d] commit batch, front-no. %d, blocks count %d", so.author, so.frontNo, len(blocks))
+		for _, blk := range blocks {
+			if !so.filter.admit(blk.Command) {
+				// the client has equivocated, and another command with the same sequence number has been executed.
+				so.logger.Errorf("[%d] skip conflicting command %s", so.author, blk.Command.Format())
+				continue
+			}
+
+			so.seqNo++
+			if !so.shadow {
+				so.exec.CommandExecution(blk, so.seqNo)
+				so.reload.Committed(blk.Command.Author, blk.Command.Sequence)
+			}
+
+			// record metrics.
+			so.metrics.CommitBlock(blk)
+		}
+	}
+}
+
+// generateBlocks generates blocks for the commands in one batch, and the batch with a single command is a safe one,
+// which means there isn't a cycle of orderings among the commands.
+func (so *sccOrdering) generateBlocks(batch types.CommandStream) []types.InnerBlock {
+	so.frontNo++
+
+	for _, info := range batch {
+		info.UpdateTrustedTS(so.oneCorrect)
+	}
+	sort.Sort(batch)
+
+	var blocks []types.InnerBlock
+	for _, info := range batch {
+		rawCommand := so.reader.ReadCommand(info.Digest, info.Referrers())
+		if rawCommand == nil {
+			return nil
+		}
+		block := types.NewInnerBlock(so.frontNo, len(batch) == 1, rawCommand, info.TrustedTS)
+		so.logger.Infof("[%d] generate block %s", so.author, block.Format())
+
+		so.cRecorder.CommittedStatus(rawCommand)
+		so.graph.remove(info.Digest)
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+//==================================== ordering graph ===========================================
+
+// orderGraph is used to maintain the ordering graph of pending commands incrementally. The edges of a command would
+// only be updated with the other pending commands once it has been ordered by another replica, instead of rebuilding
+// the whole graph for each order stream.
+type orderGraph struct {
+	// members are the identifiers of replicas whose partial orders are regarded as local orderings.
+	members []uint64
+
+	// rule is used to decide the edges between two pending commands.
+	rule edgeRule
+
+	// pending records the command infos which haven't been committed.
+	pending map[string]*types.CommandInfo
+
+	// edges records the successors of each pending command.
+	edges map[string]map[string]bool
+
+	// dirty records the pending commands whose edges should be updated.
+	dirty map[string]bool
+}
+
+func newOrderGraph(members []uint64, rule edgeRule) *orderGraph {
+	return &orderGraph{
+		members: members,
+		rule:    rule,
+		pending: make(map[string]*types.CommandInfo),
+		edges:   make(map[string]map[string]bool),
+		dirty:   make(map[string]bool),
+	}
+}
+
+// reconfigure is used to update the local orderings, and the edges of all the pending commands would be updated.
+func (graph *orderGraph) reconfigure(members []uint64) {
+	graph.members = members
+	for digest := range graph.pending {
+		graph.dirty[digest] = true
+	}
+}
+
+// update records the command which has been ordered by another replica.
+func (graph *orderGraph) update(info *types.CommandInfo) {
+	if _, ok := graph.pending[info.Digest]; !ok {
+		graph.pending[info.Digest] = info
+		graph.edges[info.Digest] = make(map[string]bool)
+	}
+	graph.dirty[info.Digest] = true
+}
+
+// remove removes the committed command with its edges.
+func (graph *orderGraph) remove(digest string) {
+	delete(graph.pending, digest)
+	delete(graph.edges, digest)
+	delete(graph.dirty, digest)
+	for _, successors := range graph.edges {
+		delete(successors, digest)
+	}
+}
+
+// batches returns the strongly connected components of the ordering graph in topological order.
+func (graph *orderGraph) batches() []types.CommandStream {
+	for digest := range graph.dirty {
+		a := graph.pending[digest]
+		for _, b := range graph.pending {
+			if a == b {
+				continue
+			}
+			ab, ba := graph.orderCounts(a, b)
+			forward, backward := graph.rule(a, b, ab, ba)
+			graph.setEdge(a.Digest, b.Digest, forward)
+			graph.setEdge(b.Digest, a.Digest, backward)
+		}
+	}
+	graph.dirty = make(map[string]bool)
+
+	infos := make(types.CommandStream, 0, len(graph.pending))
+	for _, info := range graph.pending {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Digest < infos[j].Digest })
+
+	vertices := make(map[string]int, len(infos))
+	for index, info := range infos {
+		vertices[info.Digest] = index
+	}
+	adjacency := make([][]int, len(infos))
+	for index, info := range infos {
+		for successor := range graph.edges[info.Digest] {
+			adjacency[index] = append(adjacency[index], vertices[successor])
+		}
+		sort.Ints(adjacency[index])
+	}
+
+	components := stronglyConnectedComponents(adjacency)
+
+	// the components are found in reverse topological order.
+	batches := make([]types.CommandStream, 0, len(components))
+	for index := len(components) - 1; index >= 0; index-- {
+		var batch types.CommandStream
+		for _, vertex := range components[index] {
+			batch = append(batch, infos[vertex])
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func (graph *orderGraph) setEdge(from, to string, exist bool) {
+	if exist {
+		graph.edges[from][to] = true
+		return
+	}
+	delete(graph.edges[from], to)
+}
+
+// orderCounts returns the number of replicas which have ordered a before b, and the ones which have ordered b before a.
+// The partial orders of each replica are committed in sequence, so that the replica which hasn't ordered one of them
+// would order it after the other one.
+func (graph *orderGraph) orderCounts(a, b *types.CommandInfo) (int, int) {
+	ab, ba := 0, 0
+	for _, id := range graph.members {
+		oa, okA := a.Orders[id]
+		ob, okB := b.Orders[id]
+		switch {
+		case okA && (!okB || oa.Sequence < ob.Sequence):
+			ab++
+		case okB && (!okA || ob.Sequence < oa.Sequence):
+			ba++
+		}
+	}
+	return ab, ba
+}
+
+// stronglyConnectedComponents finds the strongly connected components with Tarjan's algorithm,
+// and the components are returned in reverse topological order.
+func stronglyConnectedComponents(graph [][]int) [][]int {
+	index := 0
+	indices := make([]int, len(graph))
+	lowLinks := make([]int, len(graph))
+	onStack := make([]bool, len(graph))
+	for i := range indices {
+		indices[i] = -1
+	}
+
+	var stack []int
+	var components [][]int
+
+	var connect func(v int)
+	connect = func(v int) {
+		indices[v] = index
+		lowLinks[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range graph[v] {
+			if indices[w] == -1 {
+				connect(w)
+				lowLinks[v] = minInt(lowLinks[v], lowLinks[w])
+			} else if onStack[w] {
+				lowLinks[v] = minInt(lowLinks[v], indices[w])
+			}
+		}
+
+		if lowLinks[v] == indices[v] {
+			var component []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for v := range graph {
+		if indices[v] == -1 {
+			connect(v)
+		}
+	}
+	return components
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,162 @@
+package finality
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Grivn/phalanx/common/types"
+	"github.com/Grivn/phalanx/executor/recorder"
+)
+
+// order records that replica author has ordered the command with its sequence number.
+func order(info *types.CommandInfo, author uint64, sequence uint64) {
+	info.OrderAppend(types.OrderInfo{Author: author, Sequence: sequence, Command: info.Digest})
+}
+
+// digests returns the sorted digests of commands in each batch.
+func digests(batches []types.CommandStream) [][]string {
+	var res [][]string
+	for _, batch := range batches {
+		var ds []string
+		for _, info := range batch {
+			ds = append(ds, info.Digest)
+		}
+		sort.Strings(ds)
+		res = append(res, ds)
+	}
+	return res
+}
+
+func TestOrderGraphCycle(t *testing.T) {
+	graph := newOrderGraph([]uint64{1, 2, 3}, themisEdge)
+
+	a, b, c, d := types.NewCmdInfo("a"), types.NewCmdInfo("b"), types.NewCmdInfo("c"), types.NewCmdInfo("d")
+
+	// the replicas order a, b and c in a Condorcet cycle, and all of them order d at last.
+	for author, seq := range map[uint64][]*types.CommandInfo{1: {a, b, c, d}, 2: {b, c, a, d}, 3: {c, a, b, d}} {
+		for index, info := range seq {
+			order(info, author, uint64(index+1))
+		}
+	}
+	for _, info := range []*types.CommandInfo{d, c, b, a} {
+		graph.update(info)
+	}
+
+	expect := [][]string{{"a", "b", "c"}, {"d"}}
+	if res := digests(graph.batches()); !reflect.DeepEqual(res, expect) {
+		t.Fatalf("expect batches %v, received %v", expect, res)
+	}
+
+	// the committed batch would be removed with its edges.
+	for _, digest := range []string{"a", "b", "c"} {
+		graph.remove(digest)
+	}
+	expect = [][]string{{"d"}}
+	if res := digests(graph.batches()); !reflect.DeepEqual(res, expect) {
+		t.Fatalf("expect batches %v, received %v", expect, res)
+	}
+}
+
+func TestOrderGraphIncremental(t *testing.T) {
+	graph := newOrderGraph([]uint64{1, 2, 3}, themisEdge)
+
+	a, b := types.NewCmdInfo("a"), types.NewCmdInfo("b")
+
+	// replica 1 orders b before a, and the other replicas haven't ordered them yet.
+	order(b, 1, 1)
+	graph.update(b)
+	order(a, 1, 2)
+	graph.update(a)
+
+	expect := [][]string{{"b"}, {"a"}}
+	if res := digests(graph.batches()); !reflect.DeepEqual(res, expect) {
+		t.Fatalf("expect batches %v, received %v", expect, res)
+	}
+
+	// the other replicas order a at first, so that the edge between them should be reversed.
+	order(a, 2, 1)
+	order(a, 3, 1)
+	graph.update(a)
+
+	expect = [][]string{{"a"}, {"b"}}
+	if res := digests(graph.batches()); !reflect.DeepEqual(res, expect) {
+		t.Fatalf("expect batches %v, received %v", expect, res)
+	}
+}
+
+func TestOrderGraphUndecided(t *testing.T) {
+	// the precedence is decided by at least 3 replicas, and the command is stable with 3 partial orders.
+	aeq := &aequitasBasedOrdering{sccOrdering: &sccOrdering{stable: 3}, threshold: 3}
+	graph := newOrderGraph([]uint64{1, 2, 3, 4}, aeq.precedence)
+
+	a, b, c := types.NewCmdInfo("a"), types.NewCmdInfo("b"), types.NewCmdInfo("c")
+
+	// a and b are ordered in different orders by two replicas each, and all the replicas order c at last.
+	for author, seq := range map[uint64][]*types.CommandInfo{1: {a, b, c}, 2: {a, b, c}, 3: {b, a, c}, 4: {b, a, c}} {
+		for index, info := range seq {
+			order(info, author, uint64(index+1))
+		}
+	}
+	for _, info := range []*types.CommandInfo{a, b, c} {
+		graph.update(info)
+	}
+
+	expect := [][]string{{"a", "b"}, {"c"}}
+	if res := digests(graph.batches()); !reflect.DeepEqual(res, expect) {
+		t.Fatalf("expect batches %v, received %v", expect, res)
+	}
+}
+
+func TestOrderGraphUnstable(t *testing.T) {
+	aeq := &aequitasBasedOrdering{sccOrdering: &sccOrdering{stable: 3}, threshold: 3}
+	graph := newOrderGraph([]uint64{1, 2, 3, 4}, aeq.precedence)
+
+	a, b := types.NewCmdInfo("a"), types.NewCmdInfo("b")
+
+	// a has only been ordered by replica 1 and 2 before b, which may never become stable.
+	for author, seq := range map[uint64][]*types.CommandInfo{1: {a, b}, 2: {a, b}, 3: {b}, 4: {b}} {
+		for index, info := range seq {
+			order(info, author, uint64(index+1))
+		}
+	}
+	graph.update(a)
+	graph.update(b)
+
+	// the stable command shouldn't be halted by the unstable one it hasn't been decided with.
+	expect := [][]string{{"b"}, {"a"}}
+	if res := digests(graph.batches()); !reflect.DeepEqual(res, expect) {
+		t.Fatalf("expect batches %v, received %v", expect, res)
+	}
+}
+
+func TestAequitasStableThreshold(t *testing.T) {
+	newAequitas := func(n int, fault *int) *aequitasBasedOrdering {
+		var members []uint64
+		for id := 1; id <= n; id++ {
+			members = append(members, uint64(id))
+		}
+		aeq := &aequitasBasedOrdering{fault: fault}
+		aeq.sccOrdering = &sccOrdering{
+			graph:     newOrderGraph(members, aeq.precedence),
+			cRecorder: recorder.NewCommandRecorder(1, members, types.NewRawLogger()),
+		}
+		aeq.Reconfigure(members)
+		return aeq
+	}
+
+	// no byzantine node is tolerated with the default γ for 4 replicas, and the stable threshold is clamped to quorum,
+	// so that one crashed replica wouldn't stall the finalization.
+	if aeq := newAequitas(4, nil); aeq.stable != 3 || aeq.oneCorrect != 1 {
+		t.Fatalf("expect stable threshold 3 and one-correct 1, received %d and %d", aeq.stable, aeq.oneCorrect)
+	}
+
+	// one byzantine node is tolerated with the default γ for 13 replicas, which could be configured as zero explicitly.
+	if aeq := newAequitas(13, nil); aeq.stable != 9 || aeq.oneCorrect != 2 {
+		t.Fatalf("expect stable threshold 9 and one-correct 2, received %d and %d", aeq.stable, aeq.oneCorrect)
+	}
+	fault := 0
+	if aeq := newAequitas(13, &fault); aeq.stable != 9 || aeq.oneCorrect != 1 {
+		t.Fatalf("expect stable threshold 9 and one-correct 1, received %d and %d", aeq.stable, aeq.oneCorrect)
+	}
+}
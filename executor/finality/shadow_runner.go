@@ -0,0 +1,89 @@
+package finality
+
+import (
+	"github.com/Grivn/phalanx/common/api"
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
+)
+
+// shadowReader is used by the shadow strategies to read commands without counting the reads, so that the commands
+// would be treated as committed by command tracker once the selected strategy has read them.
+type shadowReader struct {
+	api.MetaPool
+}
+
+func (reader shadowReader) ReadCommand(commandD string, referrers []uint64) *protos.Command {
+	return reader.PeekCommand(commandD, referrers)
+}
+
+// shadowCheckpoint is the checkpoint which would be notified to meta pool once the shadow strategies have processed
+// the streams before it, so that the commands they are going to read wouldn't be garbage collected.
+type shadowCheckpoint struct {
+	checkpoint types.Checkpoint
+}
+
+// shadowMembers is the membership change for shadow strategies, which takes effect after the streams before it.
+type shadowMembers struct {
+	members []uint64
+}
+
+// shadowRunner is used to process the order streams with shadow strategies on their own goroutine,
+// so that the selected strategy wouldn't wait for the ones which only record metrics.
+type shadowRunner struct {
+	// shadows are the ordering strategies which only record metrics with the same order streams.
+	shadows []OrderingStrategy
+
+	// cache is used to record the order streams, membership changes and checkpoints in commit order.
+	// it is bounded with the stream size, and a lagging shadow would slow down the selected strategy once it is full,
+	// instead of dropping the streams.
+	cache *streamCache
+
+	// pool is used to notify meta pool the stable checkpoint.
+	pool api.MetaCheckpoint
+}
+
+func newShadowRunner(shadows []OrderingStrategy, capacity int, pool api.MetaCheckpoint) *shadowRunner {
+	return &shadowRunner{shadows: shadows, cache: newStreamCache(capacity), pool: pool}
+}
+
+func (runner *shadowRunner) run() {
+	for {
+		switch item := runner.cache.front().(type) {
+		case types.OrderStream:
+			for _, shadow := range runner.shadows {
+				shadow.CommitOrderStream(item)
+			}
+		case shadowMembers:
+			for _, shadow := range runner.shadows {
+				shadow.Reconfigure(item.members)
+			}
+		case shadowCheckpoint:
+			for _, shadow := range runner.shadows {
+				shadow.Checkpoint()
+			}
+			runner.pool.Checkpoint(item.checkpoint)
+		default:
+			// the cache has been closed.
+			return
+		}
+	}
+}
+
+func (runner *shadowRunner) commitOrderStream(oStream types.OrderStream) {
+	if len(oStream) == 0 {
+		return
+	}
+	runner.cache.push(oStream)
+}
+
+func (runner *shadowRunner) reconfigure(members []uint64) {
+	runner.cache.push(shadowMembers{members: members})
+}
+
+func (runner *shadowRunner) checkpoint(checkpoint types.Checkpoint) {
+	runner.cache.push(shadowCheckpoint{checkpoint: checkpoint})
+}
+
+func (runner *shadowRunner) quit() {
+	runner.cache.close()
+}
@@ -0,0 +1,72 @@
+package finality
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Grivn/phalanx/common/types"
+)
+
+// OrderingStrategy is used to generate the ordered blocks with the committed order stream.
+type OrderingStrategy interface {
+	// CommitOrderStream is used to process the command infos committed with a query stream.
+	CommitOrderStream(oStream types.OrderStream)
+
+	// Reconfigure is used to update the thresholds for the new membership.
+	Reconfigure(members []uint64)
+
+	// Checkpoint is used to garbage collect the states before previous checkpoint.
+	Checkpoint()
+}
+
+// StrategyConstructor is used to initiate an ordering strategy. The strategy in shadow mode only records metrics,
+// and it wouldn't execute blocks or notify meta pool the committed commands.
+type StrategyConstructor func(conf Config, members []uint64, shadow bool) OrderingStrategy
+
+var (
+	// registryMutex is used to control the concurrency problems of strategy registry.
+	registryMutex sync.RWMutex
+
+	// registry records the constructors of ordering strategies with their names.
+	registry = map[string]StrategyConstructor{
+		types.StrategyPhalanxAnchor: func(conf Config, members []uint64, shadow bool) OrderingStrategy {
+			return newPhalanxAnchorBasedOrdering(conf, members, shadow)
+		},
+		types.StrategyTimestampAnchor: func(conf Config, members []uint64, shadow bool) OrderingStrategy {
+			return newTimestampAnchorBasedOrdering(conf, members, shadow)
+		},
+		types.StrategyTimestampBased: func(conf Config, members []uint64, shadow bool) OrderingStrategy {
+			return newTimestampBasedOrdering(conf, members, shadow)
+		},
+		types.StrategyThemis: func(conf Config, members []uint64, shadow bool) OrderingStrategy {
+			return newThemisBasedOrdering(conf, members, shadow)
+		},
+		types.StrategyAequitas: func(conf Config, members []uint64, shadow bool) OrderingStrategy {
+			return newAequitasBasedOrdering(conf, members, shadow)
+		},
+	}
+)
+
+// RegisterStrategy registers an ordering strategy, which could be selected with its name in configuration.
+func RegisterStrategy(name string, constructor StrategyConstructor) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	registry[name] = constructor
+}
+
+// NewOrderingStrategy initiates the ordering strategy with given name, and a blank one refers to phalanx anchor-based ordering.
+func NewOrderingStrategy(name string, conf Config, members []uint64, shadow bool) (OrderingStrategy, error) {
+	if name == "" {
+		name = types.StrategyPhalanxAnchor
+	}
+
+	registryMutex.RLock()
+	constructor, ok := registry[name]
+	registryMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unsupported ordering strategy %s", name)
+	}
+	return constructor(conf, members, shadow), nil
+}
@@ -0,0 +1,58 @@
+package finality
+
+import (
+	"github.com/Grivn/phalanx/common/types"
+)
+
+// aequitasBasedOrdering generates blocks with the γ receive-order-fairness rule of Aequitas.
+//
+// The command a precedes the command b once at least γ fraction of replicas have ordered a before b. The pairs which
+// haven't been decided and the Condorcet cycles are the strongly connected components of the precedence graph, and
+// they are finalized as batches in topological order once each command inside has been ordered by n-f replicas, or by
+// quorum replicas if the fault threshold tolerated with γ is lower than the byzantine one.
+type aequitasBasedOrdering struct {
+	*sccOrdering
+
+	// gamma is the fairness parameter, and a zero one refers to types.DefaultFairnessGamma.
+	gamma float64
+
+	// fault is the configured fault threshold, and a nil one refers to the max amount of byzantine nodes tolerated
+	// with gamma.
+	fault *int
+
+	// threshold is the number of partial orders to decide the precedence between two commands.
+	threshold int
+}
+
+func newAequitasBasedOrdering(conf Config, members []uint64, shadow bool) *aequitasBasedOrdering {
+	aeq := &aequitasBasedOrdering{gamma: conf.Gamma, fault: conf.Fault}
+	aeq.sccOrdering = newSCCOrdering(conf, members, shadow, aeq.precedence, conf.Metrics.AequitasMetrics)
+	aeq.Reconfigure(members)
+	return aeq
+}
+
+// Reconfigure is used to update the thresholds and receive orders for the new membership.
+func (aeq *aequitasBasedOrdering) Reconfigure(members []uint64) {
+	n := len(members)
+	fault := types.CalculateFairnessFault(aeq.gamma, n)
+	if aeq.fault != nil {
+		fault = *aeq.fault
+	}
+
+	aeq.threshold = types.CalculateFairnessThreshold(aeq.gamma, n)
+	aeq.reconfigure(members, fault+1, types.CalculateStableThreshold(fault, n))
+}
+
+// precedence connects the pair without a decided precedence in both directions, so that the commands would be
+// finalized in one batch. The command ordered by fewer than n-f replicas may never become stable, e.g. it has only
+// been proposed by byzantine replicas, so that it is placed after the stable one it hasn't been decided with,
+// instead of halting the finalization of the batch.
+func (aeq *aequitasBasedOrdering) precedence(a, b *types.CommandInfo, ab, ba int) (bool, bool) {
+	if ab < aeq.threshold && ba < aeq.threshold {
+		stableA, stableB := a.OrderCount() >= aeq.stable, b.OrderCount() >= aeq.stable
+		if stableA != stableB {
+			return stableA, stableB
+		}
+	}
+	return ab >= aeq.threshold || ba < aeq.threshold, ba >= aeq.threshold || ab < aeq.threshold
+}
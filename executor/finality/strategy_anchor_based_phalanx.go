@@ -36,6 +36,9 @@ type phalanxAnchorBasedOrdering struct {
 	// frontNo is used to track the sequence number for front stream.
 	frontNo uint64
 
+	// shadow indicates current strategy only records metrics, instead of executing blocks.
+	shadow bool
+
 	//============================= internal interfaces =========================================
 
 	// reload is used to notify client instance the committed sequence number.
@@ -44,6 +47,9 @@ type phalanxAnchorBasedOrdering struct {
 	// cRecorder is used to record the command info.
 	cRecorder api.CommandRecorder
 
+	// filter is used to skip the conflicting commands issued by equivocating clients.
+	filter *commandFilter
+
 	// democracy is used to generate block with free will committee.
 	democracy map[uint64]*btree.BTree
 
@@ -65,20 +71,23 @@ type phalanxAnchorBasedOrdering struct {
 	cMetrics *metrics.CommitmentMetrics
 }
 
-func newPhalanxAnchorBasedOrdering(conf Config) *phalanxAnchorBasedOrdering {
+func newPhalanxAnchorBasedOrdering(conf Config, members []uint64, shadow bool) *phalanxAnchorBasedOrdering {
+	n := len(members)
 	democracy := make(map[uint64]*btree.BTree)
-	for i := 0; i < conf.N; i++ {
-		democracy[uint64(i+1)] = btree.New(2)
+	for _, id := range members {
+		democracy[id] = btree.New(2)
 	}
 	return &phalanxAnchorBasedOrdering{
 		author:     conf.Author,
-		fault:      types.CalculateFault(conf.N),
-		oneCorrect: types.CalculateOneCorrect(conf.N),
-		quorum:     types.CalculateQuorum(conf.N),
+		fault:      types.CalculateFault(n),
+		oneCorrect: types.CalculateOneCorrect(n),
+		quorum:     types.CalculateQuorum(n),
 		oligarchy:  conf.OLeader,
 		frontNo:    uint64(0),
+		shadow:     shadow,
 		reload:     conf.Pool,
-		cRecorder:  recorder.NewCommandRecorder(conf.Author, conf.N, conf.Logger),
+		cRecorder:  recorder.NewCommandRecorder(conf.Author, members, conf.Logger),
+		filter:     newCommandFilter(),
 		reader:     conf.Pool,
 		democracy:  democracy,
 		exec:       conf.Exec,
@@ -88,7 +97,31 @@ func newPhalanxAnchorBasedOrdering(conf Config) *phalanxAnchorBasedOrdering {
 	}
 }
 
-func (pab *phalanxAnchorBasedOrdering) commitOrderStream(oStream types.OrderStream) {
+// Reconfigure is used to update the thresholds and committee for the new membership.
+func (pab *phalanxAnchorBasedOrdering) Reconfigure(members []uint64) {
+	n := len(members)
+	democracy := make(map[uint64]*btree.BTree)
+	for _, id := range members {
+		tree, ok := pab.democracy[id]
+		if !ok {
+			tree = btree.New(2)
+		}
+		democracy[id] = tree
+	}
+	pab.democracy = democracy
+	pab.fault = types.CalculateFault(n)
+	pab.oneCorrect = types.CalculateOneCorrect(n)
+	pab.quorum = types.CalculateQuorum(n)
+	pab.cRecorder.Reconfigure(members)
+}
+
+// Checkpoint is used to garbage collect the command infos and executed commands before previous checkpoint.
+func (pab *phalanxAnchorBasedOrdering) Checkpoint() {
+	pab.cRecorder.Checkpoint()
+	pab.filter.checkpoint()
+}
+
+func (pab *phalanxAnchorBasedOrdering) CommitOrderStream(oStream types.OrderStream) {
 	if len(oStream) == 0 {
 		return
 	}
@@ -120,10 +153,22 @@ func (pab *phalanxAnchorBasedOrdering) processPartialOrder() {
 
 		// commit blocks.
 		pab.logger.Debugf("[%d] commit front group, front-no. %d, safe %v, blocks count %d", pab.author, frontNo, anchorSet.Safe, len(blocks))
+		for _, size := range anchorSet.Cycles {
+			// the precedence cycles have been resolved with trusted timestamp in current front group.
+			pab.metrics.CommitCycle(size)
+		}
 		for _, blk := range blocks {
+			if !pab.filter.admit(blk.Command) {
+				// the client has equivocated, and another command with the same sequence number has been executed.
+				pab.logger.Errorf("[%d] skip conflicting command %s", pab.author, blk.Command.Format())
+				continue
+			}
+
 			pab.seqNo++
-			pab.exec.CommandExecution(blk, pab.seqNo)
-			pab.reload.Committed(blk.Command.Author, blk.Command.Sequence)
+			if !pab.shadow {
+				pab.exec.CommandExecution(blk, pab.seqNo)
+				pab.reload.Committed(blk.Command.Author, blk.Command.Sequence)
+			}
 
 			// record metrics.
 			pab.metrics.CommitBlock(blk)
@@ -140,7 +185,7 @@ func (pab *phalanxAnchorBasedOrdering) collectPartials(oInfo types.OrderInfo) bo
 	commandD := oInfo.Command
 
 	// check if current command has been committed or not.
-	if pab.cRecorder.IsCommitted(commandD) {
+	if pab.cRecorder.IsExecuted(oInfo) {
 		pab.logger.Debugf("[%d] committed command %s, ignore it", pab.author, commandD)
 		return false
 	}
@@ -189,6 +234,7 @@ func (pab *phalanxAnchorBasedOrdering) fetchAnchorSet() types.FrontStream {
 	commands, safe := pab.cRecorder.FrontCommands()
 
 	var cStream types.CommandStream
+	var cycles []int
 	for _, digest := range commands {
 		info := pab.cRecorder.ReadCommandInfo(digest)
 		cStream = append(cStream, info)
@@ -197,11 +243,13 @@ func (pab *phalanxAnchorBasedOrdering) fetchAnchorSet() types.FrontStream {
 	if !safe {
 		if qInfo := pab.cRecorder.PickQuorumInfo(); qInfo != nil {
 			// we cannot make sure the validation of front set.
-			cStream = interceptor.NewInterceptor(pab.author, pab.cRecorder, pab.oneCorrect, pab.logger).SelectToCommit(types.CommandStream{qInfo})
+			selector := interceptor.NewInterceptor(pab.author, pab.cRecorder, pab.oneCorrect, pab.logger)
+			cStream = selector.SelectToCommit(types.CommandStream{qInfo})
+			cycles = selector.CycleSizes()
 		}
 	}
 
-	return types.FrontStream{Safe: safe, Stream: cStream}
+	return types.FrontStream{Safe: safe, Stream: cStream, Cycles: cycles}
 }
 
 func (pab *phalanxAnchorBasedOrdering) oligarchyExecution() types.FrontStream {
@@ -232,12 +280,16 @@ func (pab *phalanxAnchorBasedOrdering) freeWill(frontStream types.FrontStream) (
 		pab.cMetrics.CommitFrontCommandInfo(frontC)
 
 		// generate block, try to fetch the raw command to fulfill the block.
-		rawCommand := pab.reader.ReadCommand(frontC.Digest)
+		rawCommand := pab.reader.ReadCommand(frontC.Digest, frontC.Referrers())
+		if rawCommand == nil {
+			// meta pool has been closed.
+			return nil, pab.frontNo
+		}
 		block := types.NewInnerBlock(pab.frontNo, frontStream.Safe, rawCommand, frontC.TrustedTS)
 		pab.logger.Infof("[%d] generate block %s", pab.author, block.Format())
 
 		// finished the block generation for command (digest), update the status of digest in command recorder.
-		pab.cRecorder.CommittedStatus(frontC.Digest)
+		pab.cRecorder.CommittedStatus(rawCommand)
 
 		// append the current block into sortable slice, waiting for order-determination.
 		sortable = append(sortable, block)
@@ -36,6 +36,9 @@ type timestampAnchorBasedOrdering struct {
 	// frontNo is used to track the sequence number for front stream.
 	frontNo uint64
 
+	// shadow indicates current strategy only records metrics, instead of executing blocks.
+	shadow bool
+
 	//============================= internal interfaces =========================================
 
 	// reload is used to notify client instance the committed sequence number.
@@ -44,6 +47,9 @@ type timestampAnchorBasedOrdering struct {
 	// cRecorder is used to record the command info.
 	cRecorder api.CommandRecorder
 
+	// filter is used to skip the conflicting commands issued by equivocating clients.
+	filter *commandFilter
+
 	// democracy is used to generate block with free will committee.
 	democracy map[uint64]*btree.BTree
 
@@ -62,20 +68,23 @@ type timestampAnchorBasedOrdering struct {
 	metrics *metrics.ManipulationMetrics
 }
 
-func newTimestampAnchorBasedOrdering(conf Config) *timestampAnchorBasedOrdering {
+func newTimestampAnchorBasedOrdering(conf Config, members []uint64, shadow bool) *timestampAnchorBasedOrdering {
+	n := len(members)
 	democracy := make(map[uint64]*btree.BTree)
-	for i := 0; i < conf.N; i++ {
-		democracy[uint64(i+1)] = btree.New(2)
+	for _, id := range members {
+		democracy[id] = btree.New(2)
 	}
 	return &timestampAnchorBasedOrdering{
 		author:     conf.Author,
-		fault:      types.CalculateFault(conf.N),
-		oneCorrect: types.CalculateOneCorrect(conf.N),
-		quorum:     types.CalculateQuorum(conf.N),
+		fault:      types.CalculateFault(n),
+		oneCorrect: types.CalculateOneCorrect(n),
+		quorum:     types.CalculateQuorum(n),
 		oligarchy:  conf.OLeader,
 		frontNo:    uint64(0),
+		shadow:     shadow,
 		reload:     conf.Pool,
-		cRecorder:  recorder.NewCommandRecorder(conf.Author, conf.N, conf.Logger),
+		cRecorder:  recorder.NewCommandRecorder(conf.Author, members, conf.Logger),
+		filter:     newCommandFilter(),
 		reader:     conf.Pool,
 		democracy:  democracy,
 		exec:       conf.Exec,
@@ -84,7 +93,31 @@ func newTimestampAnchorBasedOrdering(conf Config) *timestampAnchorBasedOrdering
 	}
 }
 
-func (tab *timestampAnchorBasedOrdering) commitOrderStream(oStream types.OrderStream) {
+// Reconfigure is used to update the thresholds and committee for the new membership.
+func (tab *timestampAnchorBasedOrdering) Reconfigure(members []uint64) {
+	n := len(members)
+	democracy := make(map[uint64]*btree.BTree)
+	for _, id := range members {
+		tree, ok := tab.democracy[id]
+		if !ok {
+			tree = btree.New(2)
+		}
+		democracy[id] = tree
+	}
+	tab.democracy = democracy
+	tab.fault = types.CalculateFault(n)
+	tab.oneCorrect = types.CalculateOneCorrect(n)
+	tab.quorum = types.CalculateQuorum(n)
+	tab.cRecorder.Reconfigure(members)
+}
+
+// Checkpoint is used to garbage collect the command infos and executed commands before previous checkpoint.
+func (tab *timestampAnchorBasedOrdering) Checkpoint() {
+	tab.cRecorder.Checkpoint()
+	tab.filter.checkpoint()
+}
+
+func (tab *timestampAnchorBasedOrdering) CommitOrderStream(oStream types.OrderStream) {
 	if len(oStream) == 0 {
 		return
 	}
@@ -116,10 +149,22 @@ func (tab *timestampAnchorBasedOrdering) processPartialOrder() {
 
 		// commit blocks.
 		tab.logger.Debugf("[%d] commit front group, front-no. %d, safe %v, blocks count %d", tab.author, frontNo, anchorSet.Safe, len(blocks))
+		for _, size := range anchorSet.Cycles {
+			// the precedence cycles have been resolved with trusted timestamp in current front group.
+			tab.metrics.CommitCycle(size)
+		}
 		for _, blk := range blocks {
+			if !tab.filter.admit(blk.Command) {
+				// the client has equivocated, and another command with the same sequence number has been executed.
+				tab.logger.Errorf("[%d] skip conflicting command %s", tab.author, blk.Command.Format())
+				continue
+			}
+
 			tab.seqNo++
-			tab.exec.CommandExecution(blk, tab.seqNo)
-			tab.reload.Committed(blk.Command.Author, blk.Command.Sequence)
+			if !tab.shadow {
+				tab.exec.CommandExecution(blk, tab.seqNo)
+				tab.reload.Committed(blk.Command.Author, blk.Command.Sequence)
+			}
 
 			// record metrics.
 			tab.metrics.CommitBlock(blk)
@@ -136,7 +181,7 @@ func (tab *timestampAnchorBasedOrdering) collectPartials(oInfo types.OrderInfo)
 	commandD := oInfo.Command
 
 	// check if current command has been committed or not.
-	if tab.cRecorder.IsCommitted(commandD) {
+	if tab.cRecorder.IsExecuted(oInfo) {
 		tab.logger.Debugf("[%d] committed command %s, ignore it", tab.author, commandD)
 		return false
 	}
@@ -183,12 +228,15 @@ func (tab *timestampAnchorBasedOrdering) fetchAnchorSet() types.FrontStream {
 
 	// read the front set.
 	var cStream types.CommandStream
+	var cycles []int
 	if qInfo := tab.cRecorder.PickQuorumInfo(); qInfo != nil {
 		// we cannot make sure the validation of front set.
-		cStream = interceptor.NewInterceptor(tab.author, tab.cRecorder, tab.oneCorrect, tab.logger).SelectToCommit(types.CommandStream{qInfo})
+		selector := interceptor.NewInterceptor(tab.author, tab.cRecorder, tab.oneCorrect, tab.logger)
+		cStream = selector.SelectToCommit(types.CommandStream{qInfo})
+		cycles = selector.CycleSizes()
 	}
 
-	return types.FrontStream{Safe: false, Stream: cStream}
+	return types.FrontStream{Safe: false, Stream: cStream, Cycles: cycles}
 }
 
 func (tab *timestampAnchorBasedOrdering) oligarchyExecution() types.FrontStream {
@@ -219,12 +267,16 @@ func (tab *timestampAnchorBasedOrdering) freeWill(frontStream types.FrontStream)
 		//tab.rMetrics.CommitFrontCommandInfo(frontC)
 
 		// generate block, try to fetch the raw command to fulfill the block.
-		rawCommand := tab.reader.ReadCommand(frontC.Digest)
+		rawCommand := tab.reader.ReadCommand(frontC.Digest, frontC.Referrers())
+		if rawCommand == nil {
+			// meta pool has been closed.
+			return nil, tab.frontNo
+		}
 		block := types.NewInnerBlock(tab.frontNo, frontStream.Safe, rawCommand, frontC.TrustedTS)
 		tab.logger.Infof("[%d] generate block %s", tab.author, block.Format())
 
 		// finished the block generation for command (digest), update the status of digest in command recorder.
-		tab.cRecorder.CommittedStatus(frontC.Digest)
+		tab.cRecorder.CommittedStatus(rawCommand)
 
 		// append the current block into sortable slice, waiting for order-determination.
 		sortable = append(sortable, block)
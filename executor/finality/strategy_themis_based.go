@@ -0,0 +1,32 @@
+package finality
+
+import (
+	"github.com/Grivn/phalanx/common/types"
+)
+
+// themisBasedOrdering generates blocks with the batch-order-fairness rule of Themis.
+//
+// For each pair of pending commands, there is an edge from the one which is ordered earlier by more replicas to the
+// other one, which forms a tournament graph, and the batches are committed once each command inside has been ordered
+// by quorum replicas.
+type themisBasedOrdering struct {
+	*sccOrdering
+}
+
+func newThemisBasedOrdering(conf Config, members []uint64, shadow bool) *themisBasedOrdering {
+	tbo := &themisBasedOrdering{sccOrdering: newSCCOrdering(conf, members, shadow, themisEdge, conf.Metrics.ThemisMetrics)}
+	tbo.Reconfigure(members)
+	return tbo
+}
+
+// Reconfigure is used to update the thresholds and local orderings for the new membership.
+func (tbo *themisBasedOrdering) Reconfigure(members []uint64) {
+	tbo.reconfigure(members, types.CalculateOneCorrect(len(members)), types.CalculateQuorum(len(members)))
+}
+
+// themisEdge adds an edge from the command which is ordered earlier by more replicas to the other one,
+// and the tie is broken with digests.
+func themisEdge(a, b *types.CommandInfo, ab, ba int) (bool, bool) {
+	forward := ab > ba || (ab == ba && a.Digest < b.Digest)
+	return forward, !forward
+}
@@ -10,35 +10,59 @@ import (
 	"github.com/Grivn/phalanx/metrics"
 )
 
+// timestampBasedOrdering generates blocks with the median timestamp rule of Pompe.
+//
+// Each command is assigned with the median of the first quorum timestamps ordered for it. The partial orders of each
+// replica are committed in sequence with increasing timestamps, so that we could seal a watermark once the assigned
+// timestamp of any later command couldn't be lower than it, and the commands below the watermark are executed in
+// timestamp order.
 type timestampBasedOrdering struct {
 	//============================ basic information =============================================
 
 	// author indicates the identifier of current node.
 	author uint64
 
-	// seqNo is used to generate sequential blocks.
+	// seqNo indicates the order of inner blocks.
 	seqNo uint64
 
-	// oneCorrect indicates there is at least one correct node for bft.
-	oneCorrect int
+	// frontNo is used to track the number of sealed watermarks.
+	frontNo uint64
 
 	// quorum indicates the legal size for bft.
 	quorum int
 
-	// blocks is used to record the command info which could be committed.
-	blocks types.SortableInnerBlocks
+	// shadow indicates current strategy only records metrics, instead of executing blocks.
+	shadow bool
+
+	// members are the identifiers of replicas whose partial orders assign timestamps for commands.
+	members []uint64
+
+	// highTS records the highest timestamp in the committed partial orders of each replica.
+	highTS map[uint64]int64
+
+	// pending records the command infos which haven't collected quorum timestamps.
+	pending map[string]*types.CommandInfo
+
+	// assigned records the command infos which have been assigned with median timestamp, waiting for the watermark.
+	assigned types.CommandStream
 
 	//======================================= essential tools ===============================================
 
 	// cRecorder is used to record the command info.
 	cRecorder api.CommandRecorder
 
+	// filter is used to skip the conflicting commands issued by equivocating clients.
+	filter *commandFilter
+
 	// reload is used to notify client instance the committed sequence number.
 	reload api.MetaCommitter
 
 	// reader is used to read raw commands from meta pool.
 	reader api.MetaReader
 
+	// exec is used to execute the block.
+	exec external.ExecutionService
+
 	// metrics is used to record the metric of timestamp-based ordering.
 	metrics *metrics.ManipulationMetrics
 
@@ -46,103 +70,180 @@ type timestampBasedOrdering struct {
 	logger external.Logger
 }
 
-func newTimestampBasedOrdering(conf Config) *timestampBasedOrdering {
+func newTimestampBasedOrdering(conf Config, members []uint64, shadow bool) *timestampBasedOrdering {
+	highTS := make(map[uint64]int64, len(members))
+	for _, id := range members {
+		highTS[id] = 0
+	}
 	return &timestampBasedOrdering{
-		author:     conf.Author,
-		seqNo:      uint64(1),
-		oneCorrect: types.CalculateOneCorrect(conf.N),
-		quorum:     types.CalculateQuorum(conf.N),
-		cRecorder:  recorder.NewCommandRecorder(conf.Author, conf.N, conf.Logger),
-		reader:     conf.Pool,
-		reload:     conf.Pool,
-		metrics:    conf.Metrics.TimestampBasedMetrics,
-		logger:     conf.Logger,
+		author:    conf.Author,
+		quorum:    types.CalculateQuorum(len(members)),
+		shadow:    shadow,
+		members:   members,
+		highTS:    highTS,
+		pending:   make(map[string]*types.CommandInfo),
+		cRecorder: recorder.NewCommandRecorder(conf.Author, members, conf.Logger),
+		filter:    newCommandFilter(),
+		reader:    conf.Pool,
+		reload:    conf.Pool,
+		exec:      conf.Exec,
+		metrics:   conf.Metrics.TimestampBasedMetrics,
+		logger:    conf.Logger,
 	}
 }
 
-func (tb *timestampBasedOrdering) commitOrderStream(oStream types.OrderStream) {
+// Reconfigure is used to update the thresholds and the timestamp of each replica for the new membership.
+func (tb *timestampBasedOrdering) Reconfigure(members []uint64) {
+	highTS := make(map[uint64]int64, len(members))
+	for _, id := range members {
+		highTS[id] = tb.highTS[id]
+	}
+	tb.highTS = highTS
+	tb.members = members
+	tb.quorum = types.CalculateQuorum(len(members))
+	tb.cRecorder.Reconfigure(members)
+}
+
+// Checkpoint is used to garbage collect the command infos and executed commands before previous checkpoint.
+func (tb *timestampBasedOrdering) Checkpoint() {
+	tb.cRecorder.Checkpoint()
+	tb.filter.checkpoint()
+}
+
+func (tb *timestampBasedOrdering) CommitOrderStream(oStream types.OrderStream) {
 	if len(oStream) == 0 {
 		return
 	}
 
-	updated := false // if we have updated the command collector.
 	for _, oInfo := range oStream {
 		// order rule 1: collection rule, collect the partial order info.
-		updated = tb.collectPartials(oInfo)
+		tb.collectPartials(oInfo)
 	}
 
-	if updated {
-		// if the collector has been updated, try to process the committed partial orders.
-		tb.processPartialOrder()
-	}
+	// the timestamps of replicas have been updated, try to seal a new watermark.
+	tb.processPartialOrder()
 }
 
-func (tb *timestampBasedOrdering) collectPartials(oInfo types.OrderInfo) bool {
+func (tb *timestampBasedOrdering) collectPartials(oInfo types.OrderInfo) {
+	// the committed partial orders of current replica have passed this timestamp. The timestamps of a byzantine
+	// replica may not be increasing, so that the lower one is clamped to the highest committed one, or the commands
+	// may still be assigned with median timestamps below the sealed watermark.
+	if high, ok := tb.highTS[oInfo.Author]; ok {
+		if oInfo.Timestamp < high {
+			oInfo.Timestamp = high
+		} else {
+			tb.highTS[oInfo.Author] = oInfo.Timestamp
+		}
+	}
+
 	// find the digest for current command the partial order refers to.
 	commandD := oInfo.Command
 
 	// check if current command has been committed or not.
-	if tb.cRecorder.IsCommitted(commandD) {
+	if tb.cRecorder.IsExecuted(oInfo) {
 		tb.logger.Debugf("[%d] committed command %s, ignore it", tb.author, commandD)
-		return false
-	}
-
-	// push back partial order into recorder.queue.
-	if err := tb.cRecorder.PushBack(oInfo); err != nil {
-		tb.logger.Errorf("[%d] push back partial order failed: %s", tb.author, err)
-		return false
-	}
-
-	// already committed by quorum replicas, then update the timestamp list.
-	if tb.cRecorder.IsQuorum(commandD) {
-		// ignore the commands which have already reached quorum status.
-		return false
+		return
 	}
 
 	// read command info from command cRecorder.
 	info := tb.cRecorder.ReadCommandInfo(commandD)
+	if info.OrderCount() >= tb.quorum {
+		// the timestamp of current command has been assigned.
+		return
+	}
 	info.OrderAppend(oInfo)
-
-	// check the command status.
-	switch info.OrderCount() {
-	case tb.oneCorrect:
-		// current command has reached correct sequenced status.
-		tb.cRecorder.CorrectStatus(commandD)
-		tb.logger.Infof("[%d] found correct sequenced command %s", tb.author, commandD)
-	case tb.quorum:
-		// current command has reached quorum sequenced status.
-		tb.cRecorder.QuorumStatus(commandD)
-		tb.logger.Infof("[%d] found quorum sequenced command %s", tb.author, commandD)
-		info.UpdateTrustedTS(tb.oneCorrect)
-		rawCommand := tb.reader.ReadCommand(info.Digest)
-		block := types.NewInnerBlock(tb.seqNo, false, rawCommand, info.TrustedTS)
-		tb.blocks = append(tb.blocks, block)
+	tb.pending[commandD] = info
+
+	if info.OrderCount() == tb.quorum {
+		// current command has reached quorum sequenced status, assign the median timestamp for it.
+		sort.Sort(info.Timestamps)
+		info.TrustedTS = info.Timestamps[tb.medianIndex()]
+		delete(tb.pending, commandD)
+		tb.assigned = append(tb.assigned, info)
+		tb.logger.Infof("[%d] found quorum sequenced command %s, median timestamp %d", tb.author, commandD, info.TrustedTS)
 	}
-	return true
 }
 
-// processPartialOrder is used to process partial order with phalanx anchor-based ordering rules.
+// processPartialOrder is used to execute the commands whose median timestamps are below the sealed watermark.
 func (tb *timestampBasedOrdering) processPartialOrder() {
-	qInfos := tb.cRecorder.ReadQSCInfos()
+	if len(tb.assigned) == 0 {
+		return
+	}
 
-	if len(qInfos) < 2 {
+	watermark := tb.sealWatermark()
+
+	sort.Sort(tb.assigned)
+	index := sort.Search(len(tb.assigned), func(i int) bool { return tb.assigned[i].TrustedTS >= watermark })
+	if index == 0 {
 		return
 	}
+	sealed := tb.assigned[:index]
+	tb.assigned = append(types.CommandStream(nil), tb.assigned[index:]...)
+
+	tb.frontNo++
+	tb.logger.Debugf("[%d] seal watermark %d, front-no. %d, blocks count %d", tb.author, watermark, tb.frontNo, len(sealed))
+	for _, info := range sealed {
+		rawCommand := tb.reader.ReadCommand(info.Digest, info.Referrers())
+		if rawCommand == nil {
+			// meta pool has been closed.
+			return
+		}
+		tb.cRecorder.CommittedStatus(rawCommand)
 
-	sort.Sort(tb.blocks)
-	commitBlocks := types.SortableInnerBlocks{}
-	updateBlocks := types.SortableInnerBlocks{}
-	for index, blk := range tb.blocks {
-		if index < 2 {
-			commitBlocks = append(commitBlocks, blk)
+		blk := types.NewInnerBlock(tb.frontNo, false, rawCommand, info.TrustedTS)
+		if !tb.filter.admit(blk.Command) {
+			// the client has equivocated, and another command with the same sequence number has been executed.
+			tb.logger.Errorf("[%d] skip conflicting command %s", tb.author, blk.Command.Format())
 			continue
 		}
-		updateBlocks = append(updateBlocks, blk)
-	}
-	tb.blocks = updateBlocks
 
-	for _, blk := range commitBlocks {
+		tb.seqNo++
+		if !tb.shadow {
+			tb.exec.CommandExecution(blk, tb.seqNo)
+			tb.reload.Committed(blk.Command.Author, blk.Command.Sequence)
+		}
+
+		// record metrics.
 		tb.metrics.CommitBlock(blk)
-		tb.reload.Committed(blk.Command.Author, blk.Command.Sequence)
 	}
 }
+
+// sealWatermark returns the lowest median timestamp which could be assigned to the commands in the future.
+//
+// The later timestamps of each replica are no lower than the ones it has committed, as they are clamped when we
+// collect them. As the median of any quorum timestamps couldn't be lower than the median-index one of all the possible
+// timestamps, the watermark is limited by the highest timestamps of replicas for the unseen commands, and the ones we
+// have received for pending commands.
+func (tb *timestampBasedOrdering) sealWatermark() int64 {
+	highs := make([]int64, 0, len(tb.members))
+	for _, id := range tb.members {
+		highs = append(highs, tb.highTS[id])
+	}
+	watermark := tb.lowestMedian(highs)
+
+	for _, info := range tb.pending {
+		candidates := make([]int64, 0, len(tb.members))
+		for _, id := range tb.members {
+			if oInfo, ok := info.Orders[id]; ok {
+				candidates = append(candidates, oInfo.Timestamp)
+				continue
+			}
+			candidates = append(candidates, tb.highTS[id])
+		}
+		if lowest := tb.lowestMedian(candidates); lowest < watermark {
+			watermark = lowest
+		}
+	}
+	return watermark
+}
+
+// lowestMedian returns the lowest median of quorum timestamps selected from the candidates.
+func (tb *timestampBasedOrdering) lowestMedian(candidates []int64) int64 {
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	return candidates[tb.medianIndex()]
+}
+
+// medianIndex returns the index of median in sorted quorum timestamps.
+func (tb *timestampBasedOrdering) medianIndex() int {
+	return (tb.quorum - 1) / 2
+}
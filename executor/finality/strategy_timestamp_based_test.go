@@ -0,0 +1,56 @@
+package finality
+
+import (
+	"testing"
+
+	"github.com/Grivn/phalanx/common/types"
+	"github.com/Grivn/phalanx/executor/recorder"
+)
+
+func newTestTimestampOrdering(highTS map[uint64]int64) *timestampBasedOrdering {
+	members := []uint64{1, 2, 3, 4}
+	logger := types.NewRawLogger()
+	return &timestampBasedOrdering{
+		quorum:    types.CalculateQuorum(len(members)),
+		members:   members,
+		highTS:    highTS,
+		pending:   make(map[string]*types.CommandInfo),
+		cRecorder: recorder.NewCommandRecorder(1, members, logger),
+		filter:    newCommandFilter(),
+		logger:    logger,
+	}
+}
+
+func TestSealWatermark(t *testing.T) {
+	tb := newTestTimestampOrdering(map[uint64]int64{1: 10, 2: 20, 3: 30, 4: 40})
+
+	// the unseen commands couldn't be assigned with a median timestamp lower than the second lowest one.
+	if watermark := tb.sealWatermark(); watermark != 20 {
+		t.Fatalf("expect watermark 20, received %d", watermark)
+	}
+
+	// the pending command has been ordered with lower timestamps by replica 1 and 2.
+	info := types.NewCmdInfo("a")
+	info.OrderAppend(types.OrderInfo{Author: 1, Sequence: 1, Command: "a", Timestamp: 5})
+	info.OrderAppend(types.OrderInfo{Author: 2, Sequence: 1, Command: "a", Timestamp: 6})
+	tb.pending["a"] = info
+	if watermark := tb.sealWatermark(); watermark != 6 {
+		t.Fatalf("expect watermark 6, received %d", watermark)
+	}
+}
+
+func TestCollectPartialsClampTimestamp(t *testing.T) {
+	tb := newTestTimestampOrdering(map[uint64]int64{1: 0, 2: 0, 3: 0, 4: 0})
+
+	tb.collectPartials(types.OrderInfo{Author: 1, Sequence: 1, Command: "a", Client: types.QueryIndex{Author: 1, SeqNo: 1}, Timestamp: 50})
+
+	// replica 1 orders another command with a timestamp lower than the committed one.
+	tb.collectPartials(types.OrderInfo{Author: 1, Sequence: 2, Command: "b", Client: types.QueryIndex{Author: 2, SeqNo: 1}, Timestamp: 10})
+
+	if high := tb.highTS[1]; high != 50 {
+		t.Fatalf("expect highest timestamp 50, received %d", high)
+	}
+	if ts := tb.pending["b"].Orders[1].Timestamp; ts != 50 {
+		t.Fatalf("expect clamped timestamp 50, received %d", ts)
+	}
+}
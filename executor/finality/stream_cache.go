@@ -2,22 +2,39 @@ package finality
 
 import (
 	"container/list"
-	"github.com/Grivn/phalanx/common/types"
 	"sync"
+
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
 )
 
 type streamCache struct {
 	// mutex is used to process the concurrency of streams processing.
 	mutex sync.Mutex
 
-	// streamList is used to record the committed query stream.
+	// cond is used to notify the reader that there is a new query stream, the writer that there is room
+	// for a new one, or both of them that the cache has been closed.
+	cond *sync.Cond
+
+	// capacity is the max number of items in stream list, and a non-positive one means unbounded.
+	// the committed query streams should never be dropped or refused, so that the writer would wait for room,
+	// which slows down the consensus instead of growing the memory without bound.
+	capacity int
+
+	// closed indicates if the cache has been closed.
+	closed bool
+
+	// streamList is used to record the committed query streams and reconfigurations in commit order.
 	streamList *list.List
 }
 
-func newStreamCache() *streamCache {
-	return &streamCache{
+func newStreamCache(capacity int) *streamCache {
+	cache := &streamCache{
+		capacity:   capacity,
 		streamList: list.New(),
 	}
+	cache.cond = sync.NewCond(&cache.mutex)
+	return cache
 }
 
 func (mgr *streamCache) append(qStream types.QueryStream) {
@@ -27,22 +44,51 @@ func (mgr *streamCache) append(qStream types.QueryStream) {
 	}
 
 	// append the query stream into stream list.
+	mgr.push(qStream)
+}
+
+func (mgr *streamCache) appendReconfiguration(reconf *protos.Reconfiguration) {
+	// append the reconfiguration into stream list, so that it would take effect after the previous streams.
+	mgr.push(reconf)
+}
+
+// push blocks until there is room in the cache, and the value would be discarded once the cache has been closed.
+func (mgr *streamCache) push(value interface{}) {
 	mgr.mutex.Lock()
-	mgr.streamList.PushBack(qStream)
+	for mgr.capacity > 0 && mgr.streamList.Len() >= mgr.capacity && !mgr.closed {
+		mgr.cond.Wait()
+	}
+	if !mgr.closed {
+		mgr.streamList.PushBack(value)
+	}
 	mgr.mutex.Unlock()
+	mgr.cond.Broadcast()
 }
 
-func (mgr *streamCache) front() types.QueryStream {
+// front blocks until there is a query stream or reconfiguration in the cache, and it returns nil once the cache has been closed.
+func (mgr *streamCache) front() interface{} {
 	mgr.mutex.Lock()
 	defer mgr.mutex.Unlock()
 
-	if mgr.streamList.Len() == 0 {
-		// no values in stream list, return nil.
-		return nil
+	for mgr.streamList.Len() == 0 {
+		if mgr.closed {
+			// the cache has been closed, return nil.
+			return nil
+		}
+		mgr.cond.Wait()
 	}
 
-	// pop the first value in the stream list.
+	// pop the first value in the stream list, and notify the writer waiting for room.
 	item := mgr.streamList.Front()
 	mgr.streamList.Remove(item)
-	return item.Value.(types.QueryStream)
+	mgr.cond.Broadcast()
+	return item.Value
+}
+
+// close is used to release the reader blocked in cache.
+func (mgr *streamCache) close() {
+	mgr.mutex.Lock()
+	mgr.closed = true
+	mgr.mutex.Unlock()
+	mgr.cond.Broadcast()
 }
@@ -3,6 +3,7 @@ package interceptor
 import (
 	"github.com/Grivn/phalanx/common/api"
 	"github.com/Grivn/phalanx/common/types"
+	"github.com/Grivn/phalanx/executor/scanner"
 	"github.com/Grivn/phalanx/external"
 )
 
@@ -19,6 +20,9 @@ type interceptorImpl struct {
 	// selected is referred to the commands to be committed.
 	selected map[string]bool
 
+	// cycleSizes are the sizes of the precedence cycles in the latest selected stream.
+	cycleSizes []int
+
 	// logger is used to print logs.
 	logger external.Logger
 }
@@ -37,66 +41,137 @@ func (i *interceptorImpl) SelectToCommit(barrier types.CommandStream) types.Comm
 	return i.selection(barrier)
 }
 
+func (i *interceptorImpl) CycleSizes() []int {
+	return i.cycleSizes
+}
+
 func (i *interceptorImpl) selection(barrier types.CommandStream) types.CommandStream {
 	var frontStream types.CommandStream
 	i.selected = make(map[string]bool)
+	i.cycleSizes = nil
 
 	for _, bInfo := range barrier {
 		i.selected[bInfo.Digest] = true
 	}
 
 	correctStream := i.cRecorder.ReadCSCInfos()
-	quorumStream := i.cRecorder.ReadQSCInfos()
+
+	// the commands waiting for their potential priorities are in quorum sequenced status either.
+	quorumStream := append(i.cRecorder.ReadQSCInfos(), i.cRecorder.ReadWatInfos()...)
 
 	for _, bInfo := range barrier {
-		pointer := make(map[uint64]uint64)
-		for _, order := range bInfo.Orders {
-			pointer[order.Author] = order.Sequence
+		if i.precededByCorrect(bInfo, correctStream) {
+			return nil
 		}
 
-		for _, correctC := range correctStream {
-			count := 0
-
-			for id, seq := range pointer {
-				oInfo, ok := correctC.Orders[id]
-				if !ok || oInfo.Sequence > seq {
-					count++
-				}
-				if count == i.oneCorrect {
-					break
-				}
-			}
+		frontStream = append(frontStream, i.selectPriorities(bInfo, quorumStream)...)
+	}
 
-			if count < i.oneCorrect {
-				i.logger.Debugf("[%d] potential natural order (non-quorum): %s <- %s", i.author, correctC.Format(), bInfo.Format())
+	// the selected quorum commands may have potential natural orders with the others, select them until the closure,
+	// so that the commands in one precedence cycle would be committed in one front group.
+	for additional := frontStream; len(additional) != 0; {
+		var priorities types.CommandStream
+		for _, info := range additional {
+			if i.precededByCorrect(info, correctStream) {
 				return nil
 			}
+			priorities = append(priorities, i.selectPriorities(info, quorumStream)...)
 		}
+		frontStream = append(frontStream, priorities...)
+		additional = priorities
+	}
 
-		for _, quorumC := range quorumStream {
-			if i.selected[quorumC.Digest] {
-				continue
+	stream := append(barrier, frontStream...)
+	i.scanCycles(stream)
+	return stream
+}
+
+// precededByCorrect checks if there is a command in correct sequenced status which may have natural order before info,
+// and we should wait for it to reach quorum sequenced status before we commit info. The quorum command would be regarded
+// as a potential byzantine ordered one, which waits for such priorities to be committed.
+func (i *interceptorImpl) precededByCorrect(info *types.CommandInfo, correctStream types.CommandStream) bool {
+	preceded := false
+	var newPriorities []string
+	for _, correctC := range correctStream {
+		if i.precedes(correctC, info) {
+			i.logger.Debugf("[%d] potential natural order (non-quorum): %s <- %s", i.author, correctC.Format(), info.Format())
+			preceded = true
+			if !info.PriCmd[correctC.Digest] {
+				newPriorities = append(newPriorities, correctC.Digest)
 			}
+		}
+	}
 
-			count := 0
+	if len(newPriorities) != 0 && i.cRecorder.IsQuorum(info.Digest) {
+		i.cRecorder.PotentialByz(info, newPriorities)
+	}
+	return preceded
+}
 
-			for id, seq := range pointer {
-				oInfo, ok := quorumC.Orders[id]
-				if !ok || oInfo.Sequence > seq {
-					count++
-				}
-				if count == i.oneCorrect {
-					break
-				}
-			}
+// selectPriorities selects the quorum commands which haven't been selected and may have natural order before info.
+func (i *interceptorImpl) selectPriorities(info *types.CommandInfo, quorumStream types.CommandStream) types.CommandStream {
+	var priorities types.CommandStream
+	for _, quorumC := range quorumStream {
+		if i.selected[quorumC.Digest] {
+			continue
+		}
 
-			if count < i.oneCorrect {
-				i.logger.Debugf("[%d] potential natural order (quorum): %s <- %s", i.author, quorumC.Format(), bInfo.Format())
-				frontStream = append(frontStream, quorumC)
-				i.selected[quorumC.Digest] = true
+		if i.precedes(quorumC, info) {
+			i.logger.Debugf("[%d] potential natural order (quorum): %s <- %s", i.author, quorumC.Format(), info.Format())
+			priorities = append(priorities, quorumC)
+			i.selected[quorumC.Digest] = true
+		}
+	}
+	return priorities
+}
+
+// scanCycles scans the precedence cycles among the selected commands with Condorcet scanner, and records the size of
+// each cycle. The lowest graph of current stream is built here, instead of the lowest maps of command infos. The commands
+// in one cycle have been selected into current stream, and they would be ordered with trusted timestamp deterministically.
+func (i *interceptorImpl) scanCycles(stream types.CommandStream) {
+	lowest := make(map[string]types.CommandStream, len(stream))
+	for _, info := range stream {
+		for _, prior := range stream {
+			if prior.Digest != info.Digest && i.precedes(prior, info) {
+				lowest[info.Digest] = append(lowest[info.Digest], prior)
 			}
 		}
 	}
 
-	return append(barrier, frontStream...)
+	resolved := make(map[string]bool)
+	for _, info := range stream {
+		if resolved[info.Digest] || len(lowest[info.Digest]) == 0 {
+			// the command has been resolved in another cycle, or it is a leaf without any priorities.
+			continue
+		}
+
+		cycle := scanner.NewScanner(info, lowest).Cycle()
+		if len(cycle) == 0 {
+			continue
+		}
+
+		var digests []string
+		for _, cInfo := range cycle {
+			resolved[cInfo.Digest] = true
+			digests = append(digests, cInfo.Digest)
+		}
+		i.cycleSizes = append(i.cycleSizes, len(cycle))
+		i.logger.Infof("[%d] resolve precedence cycle %v", i.author, digests)
+	}
+}
+
+// precedes checks if the prior command may have natural order before info, which means there isn't one correct
+// replica ordering info before prior.
+func (i *interceptorImpl) precedes(prior, info *types.CommandInfo) bool {
+	count := 0
+	for _, order := range info.Orders {
+		oInfo, ok := prior.Orders[order.Author]
+		if !ok || oInfo.Sequence > order.Sequence {
+			count++
+		}
+		if count == i.oneCorrect {
+			return false
+		}
+	}
+	return true
 }
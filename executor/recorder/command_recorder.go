@@ -6,6 +6,7 @@ import (
 	"sort"
 
 	"github.com/Grivn/phalanx/common/api"
+	"github.com/Grivn/phalanx/common/protos"
 	"github.com/Grivn/phalanx/common/types"
 	"github.com/Grivn/phalanx/external"
 )
@@ -26,9 +27,23 @@ type commandRecorder struct {
 	// a partial order for current command which could be used to decide the natural order among commands.
 	mapQSC map[string]bool
 
-	// mapCmt is a map for commands which have already been committed.
+	// mapCmt is a map for commands which have already been committed since the latest checkpoint.
 	mapCmt map[string]bool
 
+	// mapStb is a map for commands which have been committed between the latest two checkpoints,
+	// and the ones committed before them have been garbage collected.
+	mapStb map[string]bool
+
+	// executed records the committed commands of clients above their watermarks with sequence numbers, since the
+	// commands of one client may be committed out of the order of sequence numbers, e.g. with median timestamps or in
+	// one batch sorted by trusted timestamps.
+	executed map[types.QueryIndex]bool
+
+	// watermarks are the sequence numbers of clients, which the commands up to have all been committed, and they are
+	// only advanced over a contiguous run of committed commands, so that the partial orders for the commands below
+	// watermark could be ignored even if their digests have been garbage collected.
+	watermarks map[uint64]uint64
+
 	// mapWat is a map for commands which have already become QSC but have some priorities.
 	mapWat map[string]bool
 
@@ -52,10 +67,10 @@ type commandRecorder struct {
 	logger external.Logger
 }
 
-func NewCommandRecorder(author uint64, n int, logger external.Logger) api.CommandRecorder {
+func NewCommandRecorder(author uint64, members []uint64, logger external.Logger) api.CommandRecorder {
+	n := len(members)
 	set := make(map[uint64]*list.List)
-	for i := 0; i < n; i++ {
-		id := uint64(i + 1)
+	for _, id := range members {
 		set[id] = list.New()
 	}
 	return &commandRecorder{
@@ -64,9 +79,13 @@ func NewCommandRecorder(author uint64, n int, logger external.Logger) api.Comman
 		mapCSC: make(map[string]bool),
 		mapQSC: make(map[string]bool),
 		mapCmt: make(map[string]bool),
+		mapStb: make(map[string]bool),
 		mapWat: make(map[string]bool),
-		mapPri: make(map[string][]*types.CommandInfo),
-		leaves: make(map[string]bool),
+
+		executed:   make(map[types.QueryIndex]bool),
+		watermarks: make(map[uint64]uint64),
+		mapPri:     make(map[string][]*types.CommandInfo),
+		leaves:     make(map[string]bool),
 
 		oneCorrect: types.CalculateOneCorrect(n),
 		quorum:     types.CalculateQuorum(n),
@@ -138,19 +157,51 @@ func (recorder *commandRecorder) QuorumStatus(commandD string) {
 	delete(recorder.mapCSC, commandD)
 }
 
-func (recorder *commandRecorder) CommittedStatus(commandD string) {
+func (recorder *commandRecorder) CommittedStatus(command *protos.Command) {
+	commandD := command.Digest
+
 	recorder.mapCmt[commandD] = true
+	recorder.executeIndex(types.QueryIndex{Author: command.Author, SeqNo: command.Sequence})
 	delete(recorder.mapQSC, commandD)
-	delete(recorder.mapCmd, commandD)
+	delete(recorder.mapWat, commandD)
 
 	recorder.prioriCommit(commandD)
 	delete(recorder.mapPri, commandD)
+	delete(recorder.mapCmd, commandD)
+}
+
+// executeIndex records the committed sequence number of client, and advances the watermark over the contiguous run.
+func (recorder *commandRecorder) executeIndex(idx types.QueryIndex) {
+	if idx.SeqNo <= recorder.watermarks[idx.Author] {
+		return
+	}
+	recorder.executed[idx] = true
+
+	watermark := recorder.watermarks[idx.Author]
+	for {
+		next := types.QueryIndex{Author: idx.Author, SeqNo: watermark + 1}
+		if !recorder.executed[next] {
+			break
+		}
+		delete(recorder.executed, next)
+		watermark++
+	}
+	recorder.watermarks[idx.Author] = watermark
 }
 
 func (recorder *commandRecorder) prioriCommit(commandD string) {
 	// notify the post commands that its priority has been committed.
 	for _, waitingInfo := range recorder.mapPri[commandD] {
+		if recorder.IsCommitted(waitingInfo.Digest) {
+			// the waiting command has been committed with its priorities in one front group.
+			continue
+		}
+
 		waitingInfo.PrioriCommit(commandD)
+		if committedInfo, ok := recorder.mapCmd[commandD]; ok {
+			// the lowest commands of the committed priority become the lowest ones of waiting command.
+			waitingInfo.TransitiveLow(committedInfo)
+		}
 		recorder.logger.Debugf("[%d] %s committed potential pri-command %s", recorder.author, waitingInfo.Format(), commandD)
 
 		if waitingInfo.PrioriFinished() {
@@ -165,7 +216,14 @@ func (recorder *commandRecorder) prioriCommit(commandD string) {
 //==================================== get command status =============================================
 
 func (recorder *commandRecorder) IsCommitted(commandD string) bool {
-	return recorder.mapCmt[commandD]
+	return recorder.mapCmt[commandD] || recorder.mapStb[commandD]
+}
+
+func (recorder *commandRecorder) IsExecuted(oInfo types.OrderInfo) bool {
+	if recorder.IsCommitted(oInfo.Command) {
+		return true
+	}
+	return oInfo.Client.SeqNo <= recorder.watermarks[oInfo.Client.Author] || recorder.executed[oInfo.Client]
 }
 
 func (recorder *commandRecorder) IsQuorum(commandD string) bool {
@@ -204,7 +262,9 @@ func (recorder *commandRecorder) PotentialByz(info *types.CommandInfo, newPriori
 
 		recorder.mapPri[priori] = append(recorder.mapPri[priori], info)
 
-		for digest, cmd := range recorder.mapCmd[priori].LowCmd {
+		priInfo := recorder.ReadCommandInfo(priori)
+		info.AppendLow(priInfo)
+		for digest, cmd := range priInfo.LowCmd {
 			info.LowCmd[digest] = cmd
 		}
 	}
@@ -214,7 +274,7 @@ func (recorder *commandRecorder) PotentialByz(info *types.CommandInfo, newPriori
 
 // PushBack pushes the partial orders into FIFO order queue for each node.
 func (recorder *commandRecorder) PushBack(oInfo types.OrderInfo) error {
-	if recorder.IsCommitted(oInfo.Command) {
+	if recorder.IsExecuted(oInfo) {
 		// ignore committed command.
 		return nil
 	}
@@ -251,7 +311,7 @@ func (recorder *commandRecorder) FrontCommands() ([]string, bool) {
 				continue
 			}
 
-			if recorder.IsCommitted(orderInfo.Command) {
+			if recorder.IsExecuted(orderInfo) {
 				queue.Remove(e)
 				continue
 			}
@@ -361,7 +421,7 @@ func (recorder *commandRecorder) OligarchyLeaderFront(leader uint64) string {
 			continue
 		}
 
-		if recorder.IsCommitted(orderInfo.Command) {
+		if recorder.IsExecuted(orderInfo) {
 			queue.Remove(e)
 			continue
 		}
@@ -369,3 +429,31 @@ func (recorder *commandRecorder) OligarchyLeaderFront(leader uint64) string {
 		return orderInfo.Command
 	}
 }
+
+//=================================== checkpoint manager ===============================================
+
+// Checkpoint is used to garbage collect the committed commands before the previous checkpoint.
+// The partial orders for them received later would still be ignored with the watermarks of clients.
+func (recorder *commandRecorder) Checkpoint() {
+	recorder.logger.Infof("[%d] garbage collect %d committed commands", recorder.author, len(recorder.mapStb))
+	recorder.mapStb = recorder.mapCmt
+	recorder.mapCmt = make(map[string]bool)
+}
+
+//=================================== epoch manager ===============================================
+
+// Reconfigure is used to rebuild the order queues and recompute the thresholds for the new membership,
+// and the queues of remaining participants would be kept.
+func (recorder *commandRecorder) Reconfigure(members []uint64) {
+	set := make(map[uint64]*list.List, len(members))
+	for _, id := range members {
+		queue, ok := recorder.fifoQueue[id]
+		if !ok {
+			queue = list.New()
+		}
+		set[id] = queue
+	}
+	recorder.fifoQueue = set
+	recorder.oneCorrect = types.CalculateOneCorrect(len(members))
+	recorder.quorum = types.CalculateQuorum(len(members))
+}
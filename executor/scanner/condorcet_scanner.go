@@ -1,6 +1,8 @@
 package scanner
 
 import (
+	"sort"
+
 	"github.com/Grivn/phalanx/common/api"
 	"github.com/Grivn/phalanx/common/types"
 )
@@ -10,11 +12,18 @@ type scanner struct {
 
 	selfInfo *types.CommandInfo
 
+	// lowest records the commands which may have natural order before each command. It is the graph scanned by us,
+	// which is built by the caller, so that the lowest maps maintained by command recorder wouldn't be changed.
+	lowest map[string]types.CommandStream
+
+	// visited records the commands we have searched, so that the cycles without target wouldn't trap us.
+	visited map[string]bool
+
 	found bool
 }
 
-func NewScanner(info *types.CommandInfo) api.CondorcetScanner {
-	return &scanner{target: info.Digest, selfInfo: info, found: false}
+func NewScanner(info *types.CommandInfo, lowest map[string]types.CommandStream) api.CondorcetScanner {
+	return &scanner{target: info.Digest, selfInfo: info, lowest: lowest, visited: make(map[string]bool), found: false}
 }
 
 func (s *scanner) HasCyclic() bool {
@@ -23,31 +32,59 @@ func (s *scanner) HasCyclic() bool {
 	return s.found
 }
 
-func (s *scanner) searchLowest(info *types.CommandInfo) map[string]*types.CommandInfo {
-	if s.found {
+// Cycle resolves the precedence cycle of target, which consists of target and the commands reachable from it which
+// could reach target either. The commands in cycle are sorted with trusted timestamp, which is deterministic among
+// participants, and they should be committed in one front group.
+func (s *scanner) Cycle() types.CommandStream {
+	if !s.HasCyclic() {
 		return nil
 	}
 
-	if len(info.LowCmd) == 0 {
-		// here is a leaf node, return the value
-		return nil
+	cycle := types.CommandStream{s.selfInfo}
+	for _, info := range s.reachable(s.selfInfo) {
+		if info.Digest == s.target {
+			continue
+		}
+		if _, ok := s.reachable(info)[s.target]; ok {
+			cycle = append(cycle, info)
+		}
 	}
+	sort.Sort(cycle)
+	return cycle
+}
 
-	for _, pInfo := range info.LowCmd {
+func (s *scanner) searchLowest(info *types.CommandInfo) {
+	if s.found || s.visited[info.Digest] {
+		return
+	}
+	s.visited[info.Digest] = true
+
+	for _, pInfo := range s.lowest[info.Digest] {
 		if pInfo.Digest == s.target {
 			// we have found target node, directly finish.
 			s.found = true
-			break
+			return
 		}
 
-		pLow := s.searchLowest(pInfo)
+		s.searchLowest(pInfo)
+	}
+}
 
-		if len(pLow) == 0 {
-			continue
-		}
+// reachable returns the commands which could be reached from info in the lowest graph.
+func (s *scanner) reachable(info *types.CommandInfo) map[string]*types.CommandInfo {
+	reached := make(map[string]*types.CommandInfo)
 
-		info.TransitiveLow(pInfo)
+	var search func(info *types.CommandInfo)
+	search = func(info *types.CommandInfo) {
+		for _, pInfo := range s.lowest[info.Digest] {
+			if _, ok := reached[pInfo.Digest]; ok {
+				continue
+			}
+			reached[pInfo.Digest] = pInfo
+			search(pInfo)
+		}
 	}
+	search(info)
 
-	return info.LowCmd
+	return reached
 }
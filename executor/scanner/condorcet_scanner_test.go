@@ -0,0 +1,32 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/Grivn/phalanx/common/types"
+)
+
+func TestScannerCycle(t *testing.T) {
+	a, b, c, d := types.NewCmdInfo("a"), types.NewCmdInfo("b"), types.NewCmdInfo("c"), types.NewCmdInfo("d")
+	a.TrustedTS, b.TrustedTS, c.TrustedTS, d.TrustedTS = 3, 1, 2, 0
+
+	// a, b and c precede each other in a cycle, and d precedes all of them.
+	lowest := map[string]types.CommandStream{
+		"a": {b, d},
+		"b": {c, d},
+		"c": {a, d},
+	}
+
+	cycle := NewScanner(a, lowest).Cycle()
+	var digests []string
+	for _, info := range cycle {
+		digests = append(digests, info.Digest)
+	}
+	if len(digests) != 3 || digests[0] != "b" || digests[1] != "c" || digests[2] != "a" {
+		t.Fatalf("expect cycle [b c a] sorted with trusted timestamp, received %v", digests)
+	}
+
+	if NewScanner(d, lowest).HasCyclic() {
+		t.Fatalf("expect no cycle for d")
+	}
+}
@@ -25,3 +25,25 @@ type PublicKey interface {
 	// Verify verifies a signature of an input message using the provided hasher.
 	Verify(*protos.Certification, types.Hash) error
 }
+
+// PublicKeyDecoder is used to decode the public keys carried by reconfiguration.
+type PublicKeyDecoder interface {
+	// DecodePublicKey decodes the public key from raw bytes.
+	DecodePublicKey(raw []byte) (PublicKey, error)
+}
+
+// PossessionVerifier is implemented by the public keys whose signatures could be aggregated, and the new ones should
+// be registered with a proof-of-possession to defend the rogue key attack.
+type PossessionVerifier interface {
+	// VerifyPossession verifies the proof-of-possession generated with the related private key.
+	VerifyPossession(proof *protos.Certification) error
+}
+
+// SignatureAggregator is an unspecified signature scheme which could aggregate the signatures on the same message.
+type SignatureAggregator interface {
+	// AggregateSignatures aggregates the signatures generated by the private keys of this scheme into one signature.
+	AggregateSignatures(certs []*protos.Certification) ([]byte, error)
+
+	// VerifyAggregatedSignature verifies the aggregated signature of an input message with the public keys of signers.
+	VerifyAggregatedSignature(signature []byte, hash types.Hash, keys []PublicKey) error
+}
@@ -1,6 +1,7 @@
 package external
 
 import (
+	"github.com/Grivn/phalanx/common/protos"
 	"github.com/Grivn/phalanx/common/types"
 )
 
@@ -9,3 +10,17 @@ type ExecutionService interface {
 	// CommandExecution is used to execute a block.
 	CommandExecution(block types.InnerBlock, seqNo uint64)
 }
+
+// MisbehaviorService provides a service to process the misbehavior of participants and clients,
+// such as slashing or excluding the offender.
+type MisbehaviorService interface {
+	// ReportMisbehavior is used to notify the verified proof of misbehavior.
+	ReportMisbehavior(proof *protos.MisbehaviorProof)
+}
+
+// ClientService provides a service to notify the clients.
+type ClientService interface {
+	// ReportGap is used to notify the client that its command with given sequence number has been skipped,
+	// as for that it has been missing for a long time and quorum replicas have moved past it.
+	ReportGap(client uint64, seqNo uint64)
+}
@@ -13,3 +13,9 @@ type NetworkService interface {
 	// UnicastPCM is used to send the message to the target node.
 	UnicastPCM(message *protos.ConsensusMessage)
 }
+
+// MembershipAware is implemented by the network service whose broadcast fan-out follows the membership of cluster.
+type MembershipAware interface {
+	// UpdateMembers is used to change the participants who would receive the broadcast messages.
+	UpdateMembers(members []uint64)
+}
@@ -5,9 +5,11 @@ require (
 	github.com/gogo/protobuf v1.3.2
 	github.com/golang/mock v1.4.4
 	github.com/google/btree v1.0.1
+	github.com/kilic/bls12-381 v0.1.0
 	github.com/sirupsen/logrus v1.8.1
+	golang.org/x/crypto v0.11.0
 )
 
-require golang.org/x/sys v0.0.0-20200930185726-fdedc70b468f // indirect
+require golang.org/x/sys v0.10.0 // indirect
 
 go 1.17
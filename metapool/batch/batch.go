@@ -0,0 +1,22 @@
+package batch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Grivn/phalanx/common/api"
+	"github.com/Grivn/phalanx/common/types"
+)
+
+// NewBatchPolicy returns the batching policy for given mode, and a blank one refers to the fixed policy.
+// The delay is the max interval to wait for more commands, and the adaptive policy would tune it below this value.
+func NewBatchPolicy(mode string, maxSize int, delay time.Duration) (api.BatchPolicy, error) {
+	switch mode {
+	case "", types.BatchFixed:
+		return NewFixedPolicy(maxSize, delay), nil
+	case types.BatchAdaptive:
+		return NewAdaptivePolicy(maxSize, types.DefaultMinBatchDelay, delay), nil
+	default:
+		return nil, fmt.Errorf("unsupported batch mode %s", mode)
+	}
+}
@@ -0,0 +1,128 @@
+package batch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Grivn/phalanx/common/api"
+)
+
+const (
+	// congestionFactor indicates a quorum latency sample above this multiple of the average one is a congestion signal.
+	congestionFactor = 1.5
+
+	// decreaseSteps is the number of additive steps to shrink the delay from max to min.
+	decreaseSteps = 10
+
+	// smoothing is the weight of a new sample in the moving averages.
+	smoothing = 0.125
+)
+
+// adaptivePolicy tunes the delay with the observed quorum latency and arrival rate in AIMD-style:
+// 1) once the votes get slower than usual, the network or the voters are congested, we double the delay,
+// so that more commands would be packed into one pre-order and there would be less pre-orders to vote for.
+// 2) otherwise, we shrink the delay with an additive step to reduce the latency of commands.
+// 3) if the commands arrive so sparsely that nothing more would be packed in the delay, we don't wait at all.
+type adaptivePolicy struct {
+	// mutex is used to control the concurrency problems of adaptive policy.
+	mutex sync.Mutex
+
+	// maxSize is the max number of commands in one pre-order, and a non-positive one means unbounded.
+	maxSize int
+
+	// minDelay and maxDelay are the bounds of delay.
+	minDelay time.Duration
+	maxDelay time.Duration
+
+	// step is the additive step to shrink the delay.
+	step time.Duration
+
+	// delay is the current interval to wait for more commands.
+	delay time.Duration
+
+	// latency is the moving average of the interval to collect quorum votes for a pre-order.
+	latency float64
+
+	// interval is the moving average of the seconds between command arrivals, and the arrival rate is its inverse.
+	// we average the intervals rather than the rates, since a burst of arrivals within nanoseconds would blow up
+	// the rate samples and dominate the average.
+	interval float64
+
+	// lastArrival is the time we have received the latest command.
+	lastArrival time.Time
+}
+
+func NewAdaptivePolicy(maxSize int, minDelay, maxDelay time.Duration) api.BatchPolicy {
+	if maxDelay < minDelay {
+		maxDelay = minDelay
+	}
+	step := (maxDelay - minDelay) / decreaseSteps
+	if step <= 0 {
+		step = minDelay
+	}
+	return &adaptivePolicy{maxSize: maxSize, minDelay: minDelay, maxDelay: maxDelay, step: step, delay: minDelay}
+}
+
+func (p *adaptivePolicy) MaxSize() int {
+	return p.maxSize
+}
+
+func (p *adaptivePolicy) Delay() time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.interval == 0 || p.delay.Seconds() < p.interval {
+		// we couldn't expect another command in current delay.
+		return p.minDelay
+	}
+	return p.delay
+}
+
+func (p *adaptivePolicy) ObserveArrival() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	if !p.lastArrival.IsZero() {
+		if interval := now.Sub(p.lastArrival).Seconds(); interval > 0 {
+			p.interval = average(p.interval, interval)
+		}
+	}
+	p.lastArrival = now
+}
+
+func (p *adaptivePolicy) ObserveQuorum(latency time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	sample := float64(latency)
+	if p.latency == 0 {
+		p.latency = sample
+		return
+	}
+
+	if sample > p.latency*congestionFactor {
+		// multiplicative increase of delay, i.e., multiplicative decrease of pre-order rate.
+		p.delay *= 2
+		if p.delay < p.step {
+			p.delay = p.step
+		}
+		if p.delay > p.maxDelay {
+			p.delay = p.maxDelay
+		}
+	} else {
+		// additive decrease of delay.
+		p.delay -= p.step
+		if p.delay < p.minDelay {
+			p.delay = p.minDelay
+		}
+	}
+	p.latency = average(p.latency, sample)
+}
+
+func average(previous, sample float64) float64 {
+	if previous == 0 {
+		return sample
+	}
+	return (1-smoothing)*previous + smoothing*sample
+}
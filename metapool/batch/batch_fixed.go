@@ -0,0 +1,33 @@
+package batch
+
+import (
+	"time"
+
+	"github.com/Grivn/phalanx/common/api"
+)
+
+// fixedPolicy generates a pre-order once the waiting list has reached the max size,
+// or the max delay has expired since the first command arrived.
+type fixedPolicy struct {
+	// maxSize is the max number of commands in one pre-order, and a non-positive one means unbounded.
+	maxSize int
+
+	// delay is the max interval to wait for more commands.
+	delay time.Duration
+}
+
+func NewFixedPolicy(maxSize int, delay time.Duration) api.BatchPolicy {
+	return &fixedPolicy{maxSize: maxSize, delay: delay}
+}
+
+func (p *fixedPolicy) MaxSize() int {
+	return p.maxSize
+}
+
+func (p *fixedPolicy) Delay() time.Duration {
+	return p.delay
+}
+
+func (p *fixedPolicy) ObserveArrival() {}
+
+func (p *fixedPolicy) ObserveQuorum(latency time.Duration) {}
@@ -4,19 +4,35 @@ import (
 	"time"
 
 	"github.com/Grivn/phalanx/common/api"
+	"github.com/Grivn/phalanx/common/types"
 	"github.com/Grivn/phalanx/external"
 	"github.com/Grivn/phalanx/metrics"
 )
 
 type Config struct {
-	Byz      bool
-	Snapping bool
-	Author   uint64
-	N        int
-	Multi    int
-	Duration time.Duration
-	Crypto   api.Crypto
-	Sender   external.NetworkService
-	Logger   external.Logger
-	Metrics  *metrics.MetaPoolMetrics
+	Byz          bool
+	Snapping     bool
+	Author       uint64
+	N            int
+	Multi        int
+	Window       int
+	Readers      int
+	QueueSize    int
+	Overload     string
+	Batch        api.BatchPolicy
+	FetchTimeout time.Duration
+	GapTimeout   time.Duration
+	VoteTimeout  time.Duration
+	WALPath      string
+	Timestamp    types.TimestampPolicy
+	CommandLimit types.CommandLimit
+	Hasher       types.Hasher
+	Crypto       api.Crypto
+	KeyDecoder   external.PublicKeyDecoder
+	ClientKeys   map[uint64]external.PublicKey
+	Misbehavior  external.MisbehaviorService
+	Clients      external.ClientService
+	Sender       external.NetworkService
+	Logger       external.Logger
+	Metrics      *metrics.MetaPoolMetrics
 }
@@ -2,6 +2,8 @@ package crypto
 
 import (
 	"fmt"
+	"runtime"
+	"sync"
 
 	"github.com/Grivn/phalanx/common/api"
 	"github.com/Grivn/phalanx/common/protos"
@@ -10,12 +12,38 @@ import (
 )
 
 type cryptoImpl struct {
+	mutex      sync.RWMutex
 	privateKey external.PrivateKey
 	publicKeys map[uint64]external.PublicKey
+
+	// aggregator is used to aggregate the signatures, and a nil one means the compact mode is disabled.
+	aggregator external.SignatureAggregator
+
+	// hasher is used to check the digests of messages in misbehavior proofs.
+	hasher types.Hasher
+
+	// workers is used to limit the number of signatures which are verified concurrently.
+	workers chan struct{}
+
+	// cache records the quorum-certs which have been verified.
+	cache *verifiedCache
 }
 
-func NewCrypto(privateKey external.PrivateKey, publicKeys map[uint64]external.PublicKey) api.Crypto {
-	return &cryptoImpl{privateKey: privateKey, publicKeys: publicKeys}
+// NewCrypto initiates the crypto module, the signatures in one quorum-cert would be verified by at most workers
+// goroutines concurrently (a non-positive one refers to the number of CPUs), and at most cacheSize verified
+// quorum-certs would be cached (a non-positive one disables the cache).
+func NewCrypto(privateKey external.PrivateKey, publicKeys map[uint64]external.PublicKey, aggregator external.SignatureAggregator, hasher types.Hasher, workers int, cacheSize int) api.Crypto {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &cryptoImpl{
+		privateKey: privateKey,
+		publicKeys: publicKeys,
+		aggregator: aggregator,
+		hasher:     hasher,
+		workers:    make(chan struct{}, workers),
+		cache:      newVerifiedCache(cacheSize),
+	}
 }
 
 func (c *cryptoImpl) PrivateSign(hash types.Hash) (*protos.Certification, error) {
@@ -23,7 +51,9 @@ func (c *cryptoImpl) PrivateSign(hash types.Hash) (*protos.Certification, error)
 }
 
 func (c *cryptoImpl) PublicVerify(cert *protos.Certification, hash types.Hash, nodeID uint64) error {
+	c.mutex.RLock()
 	verifier, ok := c.publicKeys[nodeID]
+	c.mutex.RUnlock()
 	if !ok {
 		return fmt.Errorf("cannot find verifier for node %d", nodeID)
 	}
@@ -35,13 +65,211 @@ func (c *cryptoImpl) VerifyProofCerts(digest types.Hash, pc *protos.QuorumCert,
 	if pc == nil {
 		return fmt.Errorf("nil proof-certs")
 	}
-	if len(pc.Certs) < quorum {
-		return fmt.Errorf("not enough signatures, expect %d, received %d", quorum, len(pc.Certs))
+
+	// the quorum size should be checked even if the proof-certs have been verified,
+	// since the quorum may be enlarged with the change of membership.
+	if pc.IsAggregated() {
+		if signers := len(pc.SignerList()); signers < quorum {
+			return fmt.Errorf("not enough signers, expect %d, received %d", quorum, signers)
+		}
+	} else {
+		// the duplicated signers should be rejected before we count the signatures.
+		if err := pc.CheckCanonical(); err != nil {
+			return fmt.Errorf("illegal proof-certs: %s", err)
+		}
+		if len(pc.Certs) < quorum {
+			return fmt.Errorf("not enough signatures, expect %d, received %d", quorum, len(pc.Certs))
+		}
+	}
+
+	key := fingerprint(digest, pc)
+	if c.cache.contains(key) {
+		return nil
+	}
+	generation := c.cache.currentGeneration()
+
+	if pc.IsAggregated() {
+		if err := c.VerifyAggregate(digest, pc, quorum); err != nil {
+			return err
+		}
+	} else if err := c.verifyCerts(digest, pc.Certs); err != nil {
+		return err
+	}
+
+	c.cache.add(key, generation)
+	return nil
+}
+
+// verifyCerts verifies the individual signatures concurrently.
+func (c *cryptoImpl) verifyCerts(digest types.Hash, certs []*protos.CertEntry) error {
+	errC := make(chan error, len(certs))
+	for _, entry := range certs {
+		c.workers <- struct{}{}
+		go func(entry *protos.CertEntry) {
+			defer func() { <-c.workers }()
+			if err := c.PublicVerify(entry.Cert, digest, entry.ID); err != nil {
+				errC <- fmt.Errorf("illegal cert from node %d: %s", entry.ID, err)
+				return
+			}
+			errC <- nil
+		}(entry)
+	}
+
+	var err error
+	for i := 0; i < len(certs); i++ {
+		if e := <-errC; e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (c *cryptoImpl) UpdateVerifiers(members []uint64, keys map[uint64]external.PublicKey) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	publicKeys := make(map[uint64]external.PublicKey, len(members))
+	for _, id := range members {
+		if key, ok := keys[id]; ok {
+			publicKeys[id] = key
+			continue
+		}
+		if key, ok := c.publicKeys[id]; ok {
+			publicKeys[id] = key
+			continue
+		}
+		return fmt.Errorf("cannot find public key for node %d", id)
+	}
+	c.publicKeys = publicKeys
+
+	// the verified proof-certs may be signed by the removed participants.
+	c.cache.purge()
+	return nil
+}
+
+func (c *cryptoImpl) VerifyMisbehavior(proof *protos.MisbehaviorProof, quorum int) error {
+	if proof == nil {
+		return fmt.Errorf("nil misbehavior proof")
+	}
+
+	if proof.IsClientMisbehavior() {
+		return verifyClientEquivocation(c.hasher, proof)
+	}
+
+	first, second := proof.ConflictingPreOrders()
+	if first == nil || second == nil {
+		return fmt.Errorf("missing conflicting messages")
+	}
+	if first.Digest == second.Digest {
+		return fmt.Errorf("messages are not conflicting, digest %s", first.Digest)
 	}
-	for id, cert := range pc.Certs {
-		if err := c.PublicVerify(cert, digest, id); err != nil {
-			return fmt.Errorf("illegal cert from node %d: %s", id, err)
+
+	for _, pre := range []*protos.PreOrder{first, second} {
+		if pre.Author != proof.Offender || pre.Sequence != proof.Sequence {
+			return fmt.Errorf("message of node %d sequence %d is not matched with offender %d sequence %d",
+				pre.Author, pre.Sequence, proof.Offender, proof.Sequence)
+		}
+		if err := types.CheckDigest(c.hasher, pre); err != nil {
+			return fmt.Errorf("invalid digest: %s", err)
+		}
+		if pre.Signature == nil {
+			return fmt.Errorf("nil signature of pre-order %s", pre.Digest)
+		}
+		if err := c.PublicVerify(pre.Signature, types.StringToBytes(pre.Digest), proof.Offender); err != nil {
+			return fmt.Errorf("invalid signature of pre-order %s: %s", pre.Digest, err)
+		}
+	}
+
+	if proof.Type == protos.MisbehaviorType_PARTIAL_ORDER_EQUIVOCATION {
+		for _, pOrder := range []*protos.PartialOrder{proof.FirstPartial, proof.SecondPartial} {
+			if err := c.VerifyProofCerts(types.StringToBytes(pOrder.PreOrderDigest()), pOrder.QC, quorum); err != nil {
+				return fmt.Errorf("invalid partial order %s: %s", pOrder.PreOrderDigest(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// verifyClientEquivocation checks if the proof contains two conflicting commands generated by the offender client,
+// and the signatures of commands should be verified with the public keys of clients by the caller.
+func verifyClientEquivocation(hasher types.Hasher, proof *protos.MisbehaviorProof) error {
+	first, second := proof.FirstCommand, proof.SecondCommand
+	if first == nil || second == nil {
+		return fmt.Errorf("missing conflicting commands")
+	}
+	if first.Digest == second.Digest {
+		return fmt.Errorf("commands are not conflicting, digest %s", first.Digest)
+	}
+
+	for _, command := range []*protos.Command{first, second} {
+		if command.Author != proof.Offender || command.Sequence != proof.Sequence {
+			return fmt.Errorf("command of client %d sequence %d is not matched with offender %d sequence %d",
+				command.Author, command.Sequence, proof.Offender, proof.Sequence)
 		}
+		if err := types.CheckCommandDigest(hasher, command); err != nil {
+			return fmt.Errorf("invalid command %s: %s", command.Digest, err)
+		}
+	}
+	return nil
+}
+
+func (c *cryptoImpl) Aggregated() bool {
+	return c.aggregator != nil
+}
+
+func (c *cryptoImpl) Aggregate(digest types.Hash, certs []*protos.CertEntry) (*protos.QuorumCert, error) {
+	if c.aggregator == nil {
+		return nil, fmt.Errorf("signature aggregation is disabled")
+	}
+
+	// the certs are sorted by signers, so that the aggregated signature is deterministic.
+	signers := make([]uint64, 0, len(certs))
+	signatures := make([]*protos.Certification, 0, len(certs))
+	for _, entry := range certs {
+		signers = append(signers, entry.ID)
+		signatures = append(signatures, entry.Cert)
+	}
+
+	signature, err := c.aggregator.AggregateSignatures(signatures)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate signatures failed: %s", err)
+	}
+
+	qc := &protos.QuorumCert{AggSignature: signature}
+	qc.SetSigners(signers)
+	return qc, nil
+}
+
+func (c *cryptoImpl) VerifyAggregate(digest types.Hash, pc *protos.QuorumCert, quorum int) error {
+	if c.aggregator == nil {
+		return fmt.Errorf("signature aggregation is disabled")
+	}
+	if pc == nil || !pc.IsAggregated() {
+		return fmt.Errorf("nil aggregated signature")
+	}
+	if len(pc.Certs) != 0 {
+		return fmt.Errorf("aggregated proof-certs should not carry individual signatures")
+	}
+
+	signers := pc.SignerList()
+	if len(signers) < quorum {
+		return fmt.Errorf("not enough signers, expect %d, received %d", quorum, len(signers))
+	}
+
+	c.mutex.RLock()
+	keys := make([]external.PublicKey, 0, len(signers))
+	for _, id := range signers {
+		key, ok := c.publicKeys[id]
+		if !ok {
+			c.mutex.RUnlock()
+			return fmt.Errorf("cannot find verifier for node %d", id)
+		}
+		keys = append(keys, key)
+	}
+	c.mutex.RUnlock()
+
+	if err := c.aggregator.VerifyAggregatedSignature(pc.AggSignature, digest, keys); err != nil {
+		return fmt.Errorf("illegal aggregated signature from nodes %v: %s", signers, err)
 	}
 	return nil
 }
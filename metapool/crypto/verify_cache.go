@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
+)
+
+// verifiedCache is a LRU cache of the quorum-certs which have been verified, so that the partial orders received
+// with quorum-cert messages and the high orders carried by proposals would only be verified once.
+type verifiedCache struct {
+	// mutex is used to control the concurrency problems of verified cache.
+	mutex sync.Mutex
+
+	// capacity is the max number of entries, and a non-positive one means the cache is disabled.
+	capacity int
+
+	// entries is used to find the position of a fingerprint in eviction list.
+	entries map[string]*list.Element
+
+	// evictList records the fingerprints from the most recently used one to the least recently used one.
+	evictList *list.List
+
+	// generation is increased once the cache has been purged, so that the result of a verification
+	// which was started with the previous public keys wouldn't be recorded.
+	generation uint64
+}
+
+func newVerifiedCache(capacity int) *verifiedCache {
+	return &verifiedCache{capacity: capacity, entries: make(map[string]*list.Element), evictList: list.New()}
+}
+
+func (vc *verifiedCache) contains(key string) bool {
+	if vc.capacity <= 0 {
+		return false
+	}
+
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	element, ok := vc.entries[key]
+	if !ok {
+		return false
+	}
+	vc.evictList.MoveToFront(element)
+	return true
+}
+
+func (vc *verifiedCache) currentGeneration() uint64 {
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	return vc.generation
+}
+
+func (vc *verifiedCache) add(key string, generation uint64) {
+	if vc.capacity <= 0 {
+		return
+	}
+
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	if generation != vc.generation {
+		return
+	}
+
+	if element, ok := vc.entries[key]; ok {
+		vc.evictList.MoveToFront(element)
+		return
+	}
+
+	vc.entries[key] = vc.evictList.PushFront(key)
+	if vc.evictList.Len() > vc.capacity {
+		oldest := vc.evictList.Back()
+		vc.evictList.Remove(oldest)
+		delete(vc.entries, oldest.Value.(string))
+	}
+}
+
+// purge drops all the entries, which is essential once the public keys have been changed.
+func (vc *verifiedCache) purge() {
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	vc.entries = make(map[string]*list.Element)
+	vc.evictList.Init()
+	vc.generation++
+}
+
+// fingerprint calculates the cache key for a pair of digest and quorum-cert.
+func fingerprint(digest types.Hash, pc *protos.QuorumCert) string {
+	hasher := sha256.New()
+	buf := make([]byte, 8)
+
+	writeBytes := func(data []byte) {
+		binary.BigEndian.PutUint64(buf, uint64(len(data)))
+		hasher.Write(buf)
+		hasher.Write(data)
+	}
+
+	writeBytes(digest)
+
+	// the encoding of quorum-cert is canonical, so that we could hash it directly.
+	payload, _ := pc.Marshal()
+	writeBytes(payload)
+
+	return string(hasher.Sum(nil))
+}
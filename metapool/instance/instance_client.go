@@ -1,6 +1,7 @@
 package instance
 
 import (
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -42,10 +43,29 @@ type clientInstance struct {
 	// commands is used to record the command according to its indicator.
 	commands *btree.BTree
 
+	// stalledNo is the missing sequence number which stalls current client, and 0 means there isn't a gap.
+	stalledNo uint64
+
+	// stalledTime is the time since when current client has been stalled by stalledNo.
+	stalledTime time.Time
+
+	// outbound is used to record the proposed commands waiting to be sent towards log-manager with block policy.
+	outbound []*types.CommandIndex
+
+	// flushing indicates there is a process sending the outbound commands, and the others wouldn't send them,
+	// so that the commands are sent in the order of sequence numbers.
+	flushing bool
+
 	//============================ communication channel ========================================
 
 	// commandC is used to propose command towards log-manager.
-	commandC chan<- *types.CommandIndex
+	commandC chan *types.CommandIndex
+
+	// closeC is used to release the proposing process waiting for room once log-manager has been closed.
+	closeC <-chan struct{}
+
+	// overload is the policy to process the command once commandC is full.
+	overload string
 
 	// isActive indicates current client instance's status.
 	// if it is true, there is a command from current client waiting to be processed.
@@ -63,7 +83,7 @@ type clientInstance struct {
 	logger external.Logger
 }
 
-func NewClient(author, id uint64, commandC chan<- *types.CommandIndex, activeCount *int64, logger external.Logger) api.ClientInstance {
+func NewClient(author, id uint64, commandC chan *types.CommandIndex, closeC <-chan struct{}, overload string, activeCount *int64, logger external.Logger) api.ClientInstance {
 	logger.Infof("[%d] initiate manager for client %d", author, id)
 	committedNo := make(map[uint64]bool)
 	committedNo[uint64(0)] = true
@@ -74,6 +94,8 @@ func NewClient(author, id uint64, commandC chan<- *types.CommandIndex, activeCou
 		committedNo: uint64(0),
 		commands:    btree.New(2),
 		commandC:    commandC,
+		closeC:      closeC,
+		overload:    overload,
 		isActive:    false,
 		activeCount: activeCount,
 		logger:      logger,
@@ -98,15 +120,101 @@ func (client *clientInstance) Commit(seqNo uint64) int {
 	return client.commands.Len()
 }
 
-func (client *clientInstance) Append(command *protos.Command) int {
+func (client *clientInstance) Append(command *protos.Command) (int, error) {
+	count, err := client.append(command)
+	if err != nil {
+		return count, err
+	}
+	return count, client.flush()
+}
+
+func (client *clientInstance) append(command *protos.Command) (int, error) {
 	client.mutex.Lock()
 	defer client.mutex.Unlock()
 
+	if command.Sequence <= client.proposedNo {
+		// the command has already been proposed, ignore it.
+		client.logger.Debugf("[%d] stale command %s, proposed %d", client.author, command.Format(), client.proposedNo)
+		return client.commands.Len(), nil
+	}
+
 	cIndex := types.NewCommandIndex(command)
 
+	if item := client.commands.Get(cIndex); item != nil {
+		// the waiting command would never be replaced, so that we only propose the first one for each sequence number.
+		if item.(*types.CommandIndex).Digest != cIndex.Digest {
+			client.logger.Errorf("[%d] conflicting command %s, waiting %s", client.author, cIndex.Format(), item.(*types.CommandIndex).Format())
+			return client.commands.Len(), nil
+		}
+
+		// the command may have been rejected by a full queue, and it is retried now.
+		dropped, err := client.proposeCommands()
+		return client.commands.Len(), client.attribute(cIndex, dropped, err)
+	}
+
 	client.commands.ReplaceOrInsert(cIndex)
 	client.logger.Debugf("[%d] received command %s", client.author, cIndex.Format())
 
+	dropped, err := client.proposeCommands()
+	return client.commands.Len(), client.attribute(cIndex, dropped, err)
+}
+
+// attribute returns the error of proposing commands only if it prevents the given command from being proposed,
+// the commands after the rejected one are not proposed either, and the ones before it have been proposed.
+// Otherwise, it returns types.ErrOverload for the command dropped to make room for the proposed ones.
+func (client *clientInstance) attribute(cIndex *types.CommandIndex, dropped *types.CommandIndex, err error) error {
+	if err != nil && cIndex.SeqNo > client.proposedNo {
+		return err
+	}
+	if dropped != nil {
+		return fmt.Errorf("%w: drop command %s", types.ErrOverload, dropped.Format())
+	}
+	return nil
+}
+
+func (client *clientInstance) Gap() (uint64, time.Time, bool) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	return client.stalledNo, client.stalledTime, client.stalledNo != 0
+}
+
+func (client *clientInstance) Skip(seqNo uint64) (bool, error) {
+	skipped, err := client.skip(seqNo)
+	if !skipped || err != nil {
+		return skipped, err
+	}
+	return skipped, client.flush()
+}
+
+func (client *clientInstance) skip(seqNo uint64) (bool, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if seqNo != client.proposedNo+1 || client.committedNo < seqNo {
+		// the missing command has been proposed, or quorum replicas haven't moved past it.
+		return false, nil
+	}
+
+	client.logger.Errorf("[%d] skip missing command for client %d, sequence %d", client.author, client.id, seqNo)
+	client.proposedNo = seqNo
+
+	dropped, err := client.proposeCommands()
+	if err == nil && dropped != nil {
+		err = fmt.Errorf("%w: drop command %s", types.ErrOverload, dropped.Format())
+	}
+	return true, err
+}
+
+// proposeCommands proposes the commands which are continuous with the proposed ones towards log-manager,
+// and it records the gap which stalls current client. With block policy, the commands are recorded as outbound ones,
+// which would be sent by flush without holding the mutex. Otherwise, it returns the command dropped from the full
+// queue with drop-oldest policy, and at most one command would be dropped for each round.
+func (client *clientInstance) proposeCommands() (*types.CommandIndex, error) {
+	defer client.updateGap()
+
+	var dropped *types.CommandIndex
+
 	c := client.minCommand()
 
 	for {
@@ -114,22 +222,101 @@ func (client *clientInstance) Append(command *protos.Command) int {
 			break
 		}
 
-		// the timestamp for partial ordering.
-		for {
-			current := time.Now().UnixNano()
-			if current > client.timestamp {
-				c.OTime = time.Now().UnixNano()
-				client.timestamp = current
-				break
+		if client.overload != types.OverloadReject && client.overload != types.OverloadDropOldest {
+			client.outbound = append(client.outbound, c)
+			c = client.minCommand()
+			continue
+		}
+
+		client.stamp(c)
+		err := client.feedBack(c)
+		if err != nil && client.overload == types.OverloadDropOldest && dropped == nil {
+			// the commands in queue have been accounted as proposed, so that the oldest one in queue is dropped to
+			// make room for current command, and its sequence number would be skipped as a missing one.
+			if dropped = client.dropOldest(); dropped != nil {
+				err = client.feedBack(c)
 			}
 		}
 
-		client.feedBack(c)
+		if err != nil {
+			// the command hasn't been proposed, keep it for the next round.
+			client.commands.ReplaceOrInsert(c)
+			client.proposedNo--
+			return dropped, err
+		}
 		client.activate()
 		c = client.minCommand()
 	}
 
-	return client.commands.Len()
+	return dropped, nil
+}
+
+// flush sends the outbound commands towards log-manager without holding the mutex, so that the other processes of
+// current client wouldn't be blocked while we are waiting for room in a full queue.
+func (client *clientInstance) flush() error {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.flushing {
+		// the outbound commands would be sent by another process.
+		return nil
+	}
+	client.flushing = true
+	defer func() { client.flushing = false }()
+
+	for len(client.outbound) > 0 {
+		c := client.outbound[0]
+		client.outbound = client.outbound[1:]
+		client.stamp(c)
+
+		client.mutex.Unlock()
+		err := client.feedBack(c)
+		client.mutex.Lock()
+
+		if err != nil {
+			return err
+		}
+		client.activate()
+	}
+	return nil
+}
+
+// stamp assigns the timestamp for partial ordering, which is increasing for the commands of current client.
+func (client *clientInstance) stamp(c *types.CommandIndex) {
+	for {
+		current := time.Now().UnixNano()
+		if current > client.timestamp {
+			c.OTime = current
+			client.timestamp = current
+			return
+		}
+	}
+}
+
+// dropOldest drops the oldest command in the full queue, and it returns nil if the queue has been drained.
+func (client *clientInstance) dropOldest() *types.CommandIndex {
+	select {
+	case c := <-client.commandC:
+		client.logger.Errorf("[%d] command queue is full, drop command %s", client.author, c.Format())
+		return c
+	default:
+		return nil
+	}
+}
+
+// updateGap checks if there is a missing command between the proposed ones and the waiting ones.
+func (client *clientInstance) updateGap() {
+	item := client.commands.Min()
+	if item == nil || item.(*types.CommandIndex).SeqNo == client.proposedNo+1 {
+		client.stalledNo = 0
+		return
+	}
+
+	if client.stalledNo != client.proposedNo+1 {
+		client.stalledNo = client.proposedNo + 1
+		client.stalledTime = time.Now()
+		client.logger.Debugf("[%d] client %d is stalled by missing sequence %d", client.author, client.id, client.stalledNo)
+	}
 }
 
 func (client *clientInstance) minCommand() *types.CommandIndex {
@@ -169,8 +356,32 @@ func (client *clientInstance) hibernate() {
 	client.logger.Debugf("[%d] hibernate client %d, total active instance %d", client.author, client.id, val)
 }
 
-func (client *clientInstance) feedBack(cIndex *types.CommandIndex) {
-	client.commandC <- cIndex
+func (client *clientInstance) feedBack(cIndex *types.CommandIndex) error {
+	switch client.overload {
+	case types.OverloadReject:
+		select {
+		case client.commandC <- cIndex:
+			return nil
+		default:
+			client.logger.Errorf("[%d] command queue is full, reject command %s", client.author, cIndex.Format())
+			return types.ErrOverload
+		}
+	case types.OverloadDropOldest:
+		select {
+		case client.commandC <- cIndex:
+			return nil
+		default:
+			client.logger.Errorf("[%d] command queue is full, cannot propose command %s", client.author, cIndex.Format())
+			return types.ErrOverload
+		}
+	default:
+		select {
+		case client.commandC <- cIndex:
+			return nil
+		case <-client.closeC:
+			return types.ErrClosed
+		}
+	}
 }
 
 func maxUint64(a, b uint64) uint64 {
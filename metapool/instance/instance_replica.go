@@ -1,15 +1,17 @@
 package instance
 
 import (
-	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/Grivn/phalanx/common/api"
 	"github.com/Grivn/phalanx/common/event"
 	"github.com/Grivn/phalanx/common/protos"
 	"github.com/Grivn/phalanx/common/types"
 	"github.com/Grivn/phalanx/external"
+	"github.com/Grivn/phalanx/metrics"
 	"github.com/google/btree"
 )
 
@@ -43,19 +45,42 @@ type replicaInstance struct {
 	// recorder is used to track the pre-order/order messages.
 	recorder *btree.BTree
 
+	// window is the max number of pre-orders we could vote for ahead of the highest verified partial order,
+	// so that the replica could pipeline the pre-orders instead of waiting for the quorum-cert one by one.
+	window uint64
+
 	// voted is used to record the latest no. we have verified.
 	voted uint64
 
+	// votedMap is used to record the pre-orders we have voted for which haven't been verified with quorum-cert,
+	// so that we would never vote for a conflicting pre-order with the same sequence number.
+	votedMap map[uint64]*protos.PreOrder
+
+	// policy is used to validate the timestamps in pre-orders before we vote for them.
+	policy types.TimestampPolicy
+
+	// hasher is used to check the digests of pre-orders.
+	hasher types.Hasher
+
 	//======================================= internal modules =========================================
 
 	// pTracker is used to record the partial orders from current sub instance node.
 	pTracker api.PartialTracker
 
+	// cTracker is used to query the time when we have received the commands.
+	cTracker api.CommandTracker
+
+	// wal is used to persist the votes and partial orders before we take effects on them.
+	wal api.WriteAheadLog
+
 	//==================================== crypto management =============================================
 
 	// crypto is used to generate/verify certificates.
 	crypto api.Crypto
 
+	// reporter is used to report the conflicting messages generated by current replica.
+	reporter api.MisbehaviorReporter
+
 	//======================================= external tools ===========================================
 
 	// sender is used to send votes to others.
@@ -63,22 +88,38 @@ type replicaInstance struct {
 
 	// logger is used to print logs.
 	logger external.Logger
+
+	// metrics is used to record the pre-orders we have rejected.
+	metrics *metrics.MetaPoolMetrics
 }
 
-func NewReplicaInstance(author, id uint64, pTracker api.PartialTracker, crypto api.Crypto,
-	sender external.NetworkService, logger external.Logger) api.ReplicaInstance {
-	logger.Infof("[%d] initiate the sub instance of order for replica %d", author, id)
+func NewReplicaInstance(author, id uint64, quorum int, window int, policy types.TimestampPolicy, hasher types.Hasher, pTracker api.PartialTracker, cTracker api.CommandTracker,
+	wal api.WriteAheadLog, crypto api.Crypto, reporter api.MisbehaviorReporter, sender external.NetworkService, logger external.Logger,
+	metrics *metrics.MetaPoolMetrics) api.ReplicaInstance {
+	logger.Infof("[%d] initiate the sub instance of order for replica %d, window %d", author, id, window)
+	if window <= 0 {
+		window = 1
+	}
 	return &replicaInstance{
 		author:   author,
 		id:       id,
+		quorum:   quorum,
 		trusted:  uint64(0),
 		sequence: uint64(1),
+		window:   uint64(window),
 		voted:    uint64(0),
+		votedMap: make(map[uint64]*protos.PreOrder),
 		recorder: btree.New(2),
+		policy:   policy,
+		hasher:   hasher,
 		pTracker: pTracker,
+		cTracker: cTracker,
+		wal:      wal,
 		crypto:   crypto,
+		reporter: reporter,
 		sender:   sender,
 		logger:   logger,
+		metrics:  metrics,
 	}
 }
 
@@ -94,21 +135,57 @@ func (ri *replicaInstance) ReceivePreOrder(pre *protos.PreOrder) error {
 
 	ri.logger.Infof("[%d] received a pre-order %s", ri.author, pre.Format())
 
-	if ri.sequence > pre.Sequence {
+	known, _ := ri.knownOrder(pre.Sequence)
+	if known != nil && known.Digest == pre.Digest {
+		if voted, ok := ri.votedMap[pre.Sequence]; ok && voted.Digest == pre.Digest && pre.Sequence >= ri.sequence {
+			// the pre-order author is retransmitting it, as for that our vote may have been lost.
+			ri.logger.Infof("[%d] re-send vote for retransmitted pre-order %s", ri.author, pre.Format())
+			if err := ri.sendVote(voted); err != nil {
+				return err
+			}
+		}
+
+		// duplicated pre-order.
+		return ri.processBTree()
+	}
+
+	if ri.sequence > pre.Sequence && known == nil {
 		ri.logger.Errorf("[%d] already voted on %d for replica %d", ri.author, pre.Sequence, ri.id)
 		return nil
 	}
 
-	ev := &event.OrderEvent{Status: event.OrderStatusPreOrder, Sequence: pre.Sequence, Digest: pre.Digest, Event: pre}
+	if err := types.CheckDigest(ri.hasher, pre); err != nil {
+		return fmt.Errorf("invalid digest: %s", err)
+	}
 
-	if ri.recorder.Has(ev) {
-		return ri.processBTree()
+	if pre.Signature == nil {
+		return fmt.Errorf("nil signature for pre-order %s", pre.Digest)
+	}
+	if err := ri.crypto.PublicVerify(pre.Signature, types.StringToBytes(pre.Digest), ri.id); err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
 	}
 
-	if err := types.CheckDigest(pre); err != nil {
-		return fmt.Errorf("invalid digest: %s", err)
+	if known != nil {
+		// current replica has signed another pre-order with the same sequence number.
+		ri.reportMisbehavior(&protos.MisbehaviorProof{
+			Type:           protos.MisbehaviorType_PRE_ORDER_EQUIVOCATION,
+			Offender:       ri.id,
+			Sequence:       pre.Sequence,
+			FirstPreOrder:  known,
+			SecondPreOrder: pre,
+		})
+		return nil
+	}
+
+	// the clock skew should be checked with the time we received the pre-order, since it may wait in recorder
+	// for the partial order with previous sequence number.
+	if err := ri.checkTimestamps(pre); err != nil {
+		ri.logger.Errorf("[%d] reject pre-order %s: %s", ri.author, pre.Format(), err)
+		ri.metrics.RejectPreOrder()
+		return nil
 	}
 
+	ev := &event.OrderEvent{Status: event.OrderStatusPreOrder, Sequence: pre.Sequence, Digest: pre.Digest, Event: pre}
 	ri.recorder.ReplaceOrInsert(ev)
 
 	return ri.processBTree()
@@ -120,80 +197,443 @@ func (ri *replicaInstance) ReceivePartial(pOrder *protos.PartialOrder) error {
 
 	ri.logger.Infof("[%d] received a partial order %s", ri.author, pOrder.Format())
 
+	return ri.receivePartial(pOrder, false)
+}
+
+func (ri *replicaInstance) ReceiveFetchedPartial(pOrder *protos.PartialOrder) error {
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+
+	ri.logger.Infof("[%d] received a fetched partial order %s", ri.author, pOrder.Format())
+
+	return ri.receivePartial(pOrder, true)
+}
+
+func (ri *replicaInstance) receivePartial(pOrder *protos.PartialOrder, fetched bool) error {
+	known, knownPartial := ri.knownOrder(pOrder.Sequence())
+	conflicted := known != nil && known.Digest != pOrder.PreOrderDigest()
+
+	if ri.sequence > pOrder.Sequence() && !conflicted {
+		ri.logger.Debugf("[%d] already processed partial order %d for replica %d", ri.author, pOrder.Sequence(), ri.id)
+		return nil
+	}
+
+	// the fetched partial order may be returned by any node, and the conflicting one would be used as evidence,
+	// so that we should make sure the content of pre-order matches the digest the quorum has signed on.
+	if fetched || conflicted {
+		if err := types.CheckDigest(ri.hasher, pOrder.PreOrder); err != nil {
+			return fmt.Errorf("invalid digest: %s", err)
+		}
+	}
+
 	// verify the signatures of current received partial order.
 	if err := ri.crypto.VerifyProofCerts(types.StringToBytes(pOrder.PreOrderDigest()), pOrder.QC, ri.quorum); err != nil {
 		return fmt.Errorf("invalid order: %s", err)
 	}
 
+	if conflicted {
+		// there are two partial orders, or a partial order and a signed pre-order, with the same sequence number.
+		proof := &protos.MisbehaviorProof{Offender: ri.id, Sequence: pOrder.Sequence()}
+		if knownPartial != nil {
+			proof.Type = protos.MisbehaviorType_PARTIAL_ORDER_EQUIVOCATION
+			proof.FirstPartial = knownPartial
+			proof.SecondPartial = pOrder
+		} else {
+			proof.Type = protos.MisbehaviorType_PRE_ORDER_EQUIVOCATION
+			proof.FirstPreOrder = known
+			proof.SecondPreOrder = pOrder.PreOrder
+		}
+		ri.reportMisbehavior(proof)
+		return nil
+	}
+
 	ev := &event.OrderEvent{Status: event.OrderStatusQuorumVerified, Sequence: pOrder.PreOrder.Sequence, Digest: pOrder.PreOrder.Digest, Event: pOrder}
 	ri.recorder.ReplaceOrInsert(ev)
 
 	return ri.processBTree()
 }
 
-func (ri *replicaInstance) processBTree() error {
-	item := ri.recorder.Min()
-	if item == nil {
-		return nil
+// knownOrder returns the pre-order of current replica we have accepted with given sequence number,
+// and the partial order if it has been verified with quorum-cert.
+func (ri *replicaInstance) knownOrder(sequence uint64) (*protos.PreOrder, *protos.PartialOrder) {
+	if item := ri.recorder.Get(&event.OrderEvent{Sequence: sequence}); item != nil {
+		switch ev := item.(*event.OrderEvent); ev.Status {
+		case event.OrderStatusPreOrder:
+			return ev.Event.(*protos.PreOrder), nil
+		case event.OrderStatusQuorumVerified:
+			pOrder := ev.Event.(*protos.PartialOrder)
+			return pOrder.PreOrder, pOrder
+		}
 	}
-	ev := item.(*event.OrderEvent)
 
-	switch ev.Status {
-	case event.OrderStatusPreOrder:
-		if ev.Sequence != ri.sequence {
-			ri.logger.Debugf("[%d] sub-instance for node %d needs sequence %d", ri.author, ri.id, ri.sequence)
-			return nil
+	if sequence < ri.sequence {
+		if pOrder := ri.pTracker.GetPartial(types.QueryIndex{Author: ri.id, SeqNo: sequence}); pOrder != nil {
+			return pOrder.PreOrder, pOrder
 		}
+	}
 
-		// parsing the event info
-		pre := ev.Event.(*protos.PreOrder)
+	if pre, ok := ri.votedMap[sequence]; ok {
+		return pre, nil
+	}
+	return nil, nil
+}
 
-		// generate the signature for current pre-order
-		sig, err := ri.crypto.PrivateSign(types.StringToBytes(pre.Digest))
-		if err != nil {
-			return fmt.Errorf("signer failed: %s", err)
-		}
+// reportMisbehavior verifies the proof we have generated before reporting it, so that the evidence
+// we sent to others would always be valid.
+func (ri *replicaInstance) reportMisbehavior(proof *protos.MisbehaviorProof) {
+	if err := ri.crypto.VerifyMisbehavior(proof, ri.quorum); err != nil {
+		ri.logger.Errorf("[%d] found conflicting messages from replica %d without valid proof: %s", ri.author, ri.id, err)
+		return
+	}
+	ri.reporter.ReportMisbehavior(proof)
+}
 
-		// generate and send vote to the pre-order author
-		vote := &protos.Vote{Author: ri.author, Digest: pre.Digest, Certification: sig}
-		ri.logger.Infof("[%d] voted %s for %s", ri.author, vote.Format(), pre.Format())
-		ri.recorder.Delete(item)
+func (ri *replicaInstance) UpdateQuorum(quorum int) {
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+	ri.quorum = quorum
+}
 
-		cm, err := protos.PackVote(vote, pre.Author)
-		if err != nil {
-			return fmt.Errorf("generate consensus message error: %s", err)
+func (ri *replicaInstance) Recover(entry *protos.WALEntry) error {
+	ri.mutex.Lock()
+	defer ri.mutex.Unlock()
+
+	switch entry.Type {
+	case protos.WALEntryType_WAL_VOTE:
+		ri.votedMap[entry.PreOrder.Sequence] = entry.PreOrder
+		if entry.PreOrder.Sequence > ri.voted {
+			ri.voted = entry.PreOrder.Sequence
 		}
-		ri.sender.UnicastPCM(cm)
 		return nil
 
-	case event.OrderStatusQuorumVerified:
-		ri.logger.Infof("[%d] process partial order event", ri.author)
+	case protos.WALEntryType_WAL_PARTIAL:
+		pOrder := entry.Partial
+		if pOrder.Sequence() < ri.sequence {
+			// the partial order has been persisted again after the snapshot we have compacted with.
+			ri.logger.Debugf("[%d] already recovered partial order %d for replica %d", ri.author, pOrder.Sequence(), ri.id)
+			return nil
+		}
+		if pOrder.Sequence() != ri.sequence {
+			return fmt.Errorf("invalid partial order sequence for replica %d, expect %d, received %d", ri.id, ri.sequence, pOrder.Sequence())
+		}
 
-		pOrder := ev.Event.(*protos.PartialOrder)
+		// the partial order has been verified before we persist it.
+		ri.pTracker.RecordPartial(pOrder)
+		ri.updateHighestOrder(pOrder)
+		ri.advance()
+		return nil
 
-		// verify the validation between current partial order and highest partial order.
-		if err := ri.checkHighestOrder(pOrder); err != nil {
+	case protos.WALEntryType_WAL_BASE_PARTIAL:
+		pOrder := entry.Partial
+		if pOrder.Sequence() < ri.sequence {
 			return nil
-			//return fmt.Errorf("check higest order failed, %s", err)
 		}
 
-		// record partial order with partial tracker.
+		// the partial orders below the base one have been compacted, and it is used to link the following ones.
+		// it would be dropped by partial tracker once it has been committed.
 		ri.pTracker.RecordPartial(pOrder)
-
-		// update the highest partial order for current sub instance.
 		ri.updateHighestOrder(pOrder)
+		ri.sequence = pOrder.Sequence()
+		ri.advance()
+		return nil
+
+	default:
+		return fmt.Errorf("invalid entry type %s for replica instance", entry.Type)
+	}
+}
+
+func (ri *replicaInstance) Snapshot(committed uint64) []*protos.WALEntry {
+	ri.mutex.RLock()
+	defer ri.mutex.RUnlock()
+
+	var entries []*protos.WALEntry
+
+	if ri.highPartialOrder != nil {
+		// collect the continuous partial orders from the highest one down to the committed one.
+		chain := []*protos.PartialOrder{ri.highPartialOrder}
+		for seq := ri.highPartialOrder.Sequence() - 1; seq >= committed && seq > 0; seq-- {
+			pOrder := ri.pTracker.GetPartial(types.QueryIndex{Author: ri.id, SeqNo: seq})
+			if pOrder == nil {
+				break
+			}
+			chain = append(chain, pOrder)
+		}
+
+		entries = append(entries, &protos.WALEntry{Type: protos.WALEntryType_WAL_BASE_PARTIAL, Partial: chain[len(chain)-1]})
+		for index := len(chain) - 2; index >= 0; index-- {
+			entries = append(entries, &protos.WALEntry{Type: protos.WALEntryType_WAL_PARTIAL, Partial: chain[index]})
+		}
+	}
+
+	// the votes are kept, so that we won't vote for a conflicting pre-order after a restart.
+	var votes []*protos.PreOrder
+	for _, pre := range ri.votedMap {
+		votes = append(votes, pre)
+	}
+	sort.Slice(votes, func(i, j int) bool { return votes[i].Sequence < votes[j].Sequence })
+	for _, pre := range votes {
+		entries = append(entries, &protos.WALEntry{Type: protos.WALEntryType_WAL_VOTE, PreOrder: pre})
+	}
+	return entries
+}
+
+func (ri *replicaInstance) processBTree() error {
+	for {
+		// drop the stale events whose sequence numbers have been verified.
+		for item := ri.recorder.Min(); item != nil && item.(*event.OrderEvent).Sequence < ri.sequence; item = ri.recorder.Min() {
+			ri.recorder.Delete(item)
+		}
 
-		ri.recorder.Delete(ev)
-		ri.sequence++
+		processed, err := ri.processPartial()
+		if err != nil {
+			return err
+		}
+		if processed {
+			continue
+		}
 
-		if err := ri.processBTree(); err != nil {
+		processed, err = ri.processPreOrder()
+		if err != nil {
 			return err
 		}
+		if !processed {
+			return nil
+		}
+	}
+}
+
+// processPartial verifies the partial order with the expected sequence number.
+func (ri *replicaInstance) processPartial() (bool, error) {
+	item := ri.recorder.Get(&event.OrderEvent{Sequence: ri.sequence})
+	if item == nil {
+		return false, nil
+	}
+	ev := item.(*event.OrderEvent)
+	if ev.Status != event.OrderStatusQuorumVerified {
+		return false, nil
+	}
+
+	ri.logger.Infof("[%d] process partial order event", ri.author)
+
+	pOrder := ev.Event.(*protos.PartialOrder)
+
+	// verify the validation between current partial order and highest partial order.
+	if err := ri.checkHighestOrder(pOrder); err != nil {
+		return false, nil
+		//return fmt.Errorf("check higest order failed, %s", err)
+	}
+
+	// persist the partial order, so that we could resume the sub-chain of current replica after a restart.
+	if err := ri.wal.Append(&protos.WALEntry{Type: protos.WALEntryType_WAL_PARTIAL, Partial: pOrder}); err != nil {
+		return false, fmt.Errorf("persist partial order failed: %s", err)
+	}
+
+	// record partial order with partial tracker.
+	ri.pTracker.RecordPartial(pOrder)
+
+	// update the highest partial order for current sub instance.
+	ri.updateHighestOrder(pOrder)
+
+	ri.recorder.Delete(ev)
+	ri.advance()
+	return true, nil
+}
+
+// processPreOrder votes for the next pre-order in the window, which should be linked to the one we have voted for
+// or verified with previous sequence number.
+func (ri *replicaInstance) processPreOrder() (bool, error) {
+	next := ri.voted + 1
+	if next < ri.sequence {
+		next = ri.sequence
+	}
+	if next >= ri.sequence+ri.window {
+		ri.logger.Debugf("[%d] sub-instance for node %d reached window, verified %d, voted %d", ri.author, ri.id, ri.sequence-1, ri.voted)
+		return false, nil
+	}
+
+	item := ri.recorder.Get(&event.OrderEvent{Sequence: next})
+	if item == nil {
+		ri.logger.Debugf("[%d] sub-instance for node %d needs sequence %d", ri.author, ri.id, next)
+		return false, nil
+	}
+	ev := item.(*event.OrderEvent)
+	if ev.Status != event.OrderStatusPreOrder {
+		return false, nil
+	}
+
+	// parsing the event info
+	pre := ev.Event.(*protos.PreOrder)
+
+	if voted, ok := ri.votedMap[pre.Sequence]; ok && pre.Digest != voted.Digest {
+		// we have voted for another pre-order with the same sequence number, reject it.
+		ri.logger.Errorf("[%d] refuse to vote for conflicting pre-order %s, voted %s", ri.author, pre.Format(), voted.Digest)
+		ri.recorder.Delete(item)
+		return true, nil
+	}
+
+	parent := ri.parentOrder(pre.Sequence)
+	if pre.Sequence > 1 {
+		if parent == nil {
+			// we should wait for the parent pre-order, or the partial order for it.
+			return false, nil
+		}
+		if parent.Digest != pre.ParentDigest {
+			ri.logger.Errorf("[%d] refuse to vote for pre-order %s, expect parent %s, received %s", ri.author, pre.Format(), parent.Digest, pre.ParentDigest)
+			ri.recorder.Delete(item)
+			return true, nil
+		}
+	}
+
+	// the commands we have received are essential to validate client indices.
+	if err := ri.checkClients(pre); err != nil {
+		ri.logger.Errorf("[%d] reject pre-order %s: %s", ri.author, pre.Format(), err)
+		ri.metrics.RejectPreOrder()
+		ri.recorder.Delete(item)
+		return true, nil
+	}
+
+	// the parent of current pre-order and the commands we have received are essential to validate timestamps.
+	if err := ri.checkOrderedTimestamps(pre, parent); err != nil {
+		ri.logger.Errorf("[%d] reject pre-order %s: %s", ri.author, pre.Format(), err)
+		ri.metrics.RejectPreOrder()
+		ri.recorder.Delete(item)
+		return true, nil
+	}
+
+	// persist the vote before we send it, so that we won't vote for a conflicting pre-order after a restart.
+	if err := ri.wal.Append(&protos.WALEntry{Type: protos.WALEntryType_WAL_VOTE, PreOrder: pre}); err != nil {
+		return false, fmt.Errorf("persist vote failed: %s", err)
+	}
+	ri.voted = pre.Sequence
+	ri.votedMap[pre.Sequence] = pre
+	ri.recorder.Delete(item)
+
+	if err := ri.sendVote(pre); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sendVote generates the vote for pre-order and sends it to the pre-order author.
+// The vote is idempotent, so that it could be sent again once the author is retransmitting the pre-order.
+func (ri *replicaInstance) sendVote(pre *protos.PreOrder) error {
+	// generate the signature for current pre-order
+	sig, err := ri.crypto.PrivateSign(types.StringToBytes(pre.Digest))
+	if err != nil {
+		return fmt.Errorf("signer failed: %s", err)
+	}
+
+	// generate and send vote to the pre-order author
+	vote := &protos.Vote{Author: ri.author, Digest: pre.Digest, Certification: sig}
+	ri.logger.Infof("[%d] voted %s for %s", ri.author, vote.Format(), pre.Format())
+
+	cm, err := protos.PackVote(vote, pre.Author)
+	if err != nil {
+		return fmt.Errorf("generate consensus message error: %s", err)
+	}
+	ri.sender.UnicastPCM(cm)
+	return nil
+}
+
+// parentOrder returns the pre-order with previous sequence number which has been verified or voted.
+func (ri *replicaInstance) parentOrder(sequence uint64) *protos.PreOrder {
+	if ri.highPartialOrder != nil && ri.highPartialOrder.Sequence()+1 == sequence {
+		return ri.highPartialOrder.PreOrder
+	}
+	return ri.votedMap[sequence-1]
+}
+
+// advance moves the expected sequence number forward once a partial order has been verified,
+// and the voted pre-orders below it are no longer essential.
+func (ri *replicaInstance) advance() {
+	ri.sequence++
+	for seq := range ri.votedMap {
+		if seq+1 < ri.sequence {
+			delete(ri.votedMap, seq)
+		}
+	}
+}
+
+// checkTimestamps checks the timestamps of pre-order with the time we have received it.
+func (ri *replicaInstance) checkTimestamps(pre *protos.PreOrder) error {
+	// the timestamps would be referred by command info, so that the length should always be checked.
+	if len(pre.TimestampList) != len(pre.CommandList) {
+		return fmt.Errorf("invalid timestamp list size, expect %d, received %d", len(pre.CommandList), len(pre.TimestampList))
+	}
+
+	// the client indices would be referred by command recorder to skip the executed commands.
+	if len(pre.ClientList) != len(pre.CommandList) || len(pre.ClientSeqList) != len(pre.CommandList) {
+		return fmt.Errorf("invalid client list size, expect %d, received %d and %d", len(pre.CommandList), len(pre.ClientList), len(pre.ClientSeqList))
+	}
+
+	if !ri.policy.Enabled || ri.policy.MaxSkew <= 0 {
+		return nil
+	}
+
+	// only the timestamps from future are rejected here, since the retransmitted pre-orders and the ones waiting
+	// for their parents would be received long after their generation, and the backdated timestamps are checked
+	// with the time we received the commands.
+	now := time.Now().UnixNano()
+	skew := int64(ri.policy.MaxSkew)
+	for index, timestamp := range pre.TimestampList {
+		if timestamp > now+skew {
+			return fmt.Errorf("timestamp %d of command %s is ahead of clock skew window, local time %d", timestamp, pre.CommandList[index], now)
+		}
+	}
+	return nil
+}
+
+// checkClients checks the client indices of pre-order with the commands we have received.
+func (ri *replicaInstance) checkClients(pre *protos.PreOrder) error {
+	for index, digest := range pre.CommandList {
+		// the commands we haven't received couldn't be judged here, and they would be checked by the voters
+		// who have received them.
+		command := ri.cTracker.GetCommand(digest)
+		if command == nil {
+			continue
+		}
+		if command.Author != pre.ClientList[index] || command.Sequence != pre.ClientSeqList[index] {
+			return fmt.Errorf("command %s is issued by client %d with sequence %d, received client %d with sequence %d",
+				digest, command.Author, command.Sequence, pre.ClientList[index], pre.ClientSeqList[index])
+		}
+	}
+	return nil
+}
+
+// checkOrderedTimestamps checks the timestamps of pre-order with its parent and the commands we have received.
+func (ri *replicaInstance) checkOrderedTimestamps(pre *protos.PreOrder, parent *protos.PreOrder) error {
+	if !ri.policy.Enabled {
 		return nil
+	}
 
-	default:
-		return errors.New("invalid event type")
+	if ri.policy.Monotonic {
+		previous := int64(0)
+		if parent != nil && len(parent.TimestampList) > 0 {
+			previous = parent.TimestampList[len(parent.TimestampList)-1]
+		}
+		for _, timestamp := range pre.TimestampList {
+			if timestamp < previous {
+				return fmt.Errorf("timestamps are not monotonic, %d is smaller than %d", timestamp, previous)
+			}
+			previous = timestamp
+		}
 	}
+
+	if ri.policy.MaxSeenDelay > 0 {
+		delay := int64(ri.policy.MaxSeenDelay)
+		for index, digest := range pre.CommandList {
+			// the commands we haven't received couldn't be judged here, and they would be checked by the voters
+			// who have received them.
+			rTime, ok := ri.cTracker.ReceivedTime(digest)
+			if !ok {
+				continue
+			}
+			if rTime-pre.TimestampList[index] > delay {
+				return fmt.Errorf("command %s is backdated, timestamp %d, received at %d", digest, pre.TimestampList[index], rTime)
+			}
+		}
+	}
+	return nil
 }
 
 func (ri *replicaInstance) updateHighestOrder(pOrder *protos.PartialOrder) {
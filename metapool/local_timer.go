@@ -33,6 +33,10 @@ func newLocalTimer(author uint64, timeoutC chan bool, duration time.Duration, lo
 	}
 }
 
+func (timer *localTimer) updateDuration(duration time.Duration) {
+	timer.duration = duration
+}
+
 // startTimer starts current timer.
 func (timer *localTimer) startTimer() {
 	timer.logger.Debugf("[%d] start partial order generation timer, duration %v", timer.author, timer.duration)
@@ -1,6 +1,8 @@
 package metapool
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
@@ -12,6 +14,7 @@ import (
 	"github.com/Grivn/phalanx/external"
 	"github.com/Grivn/phalanx/metapool/instance"
 	"github.com/Grivn/phalanx/metapool/tracker"
+	"github.com/Grivn/phalanx/metapool/wal"
 	"github.com/Grivn/phalanx/metrics"
 )
 
@@ -27,6 +30,23 @@ type metaPool struct {
 	// n indicates the number of participants in current cluster.
 	n int
 
+	// epoch indicates the epoch number of current membership.
+	epoch uint64
+
+	// members are the sorted identifiers of participants in current membership,
+	// and the partial order batch is indexed according to it.
+	members []uint64
+
+	// pending is the membership change which would be proposed with the next partial order batch.
+	pending *protos.Reconfiguration
+
+	// history records the membership changes we have committed, which would be kept in write-ahead log
+	// after compaction, so that the public keys of each epoch could be recovered.
+	history []*protos.Reconfiguration
+
+	// decoder is used to decode the public keys carried by reconfiguration.
+	decoder external.PublicKeyDecoder
+
 	// multi indicates the number of proposers each node maintains.
 	multi int
 
@@ -50,7 +70,11 @@ type metaPool struct {
 	// as for that we should be responsible for the order of our own private chain, each block could
 	highOrder *protos.PreOrder
 
-	// aggMap is used to generate aggregated-certificates.
+	// window is the max number of pre-orders waiting for quorum votes, and a new pre-order would only be generated
+	// once there is room for it.
+	window int
+
+	// aggMap is used to generate aggregated-certificates, which could aggregate votes for several pre-orders at once.
 	aggMap map[string]*protos.PartialOrder
 
 	// replicas is the module for us to process consensus messages for participates.
@@ -60,6 +84,9 @@ type metaPool struct {
 	// pTracker is used to record the partial orders received by current node.
 	pTracker api.PartialTracker
 
+	// builder is used to initiate the replica instance for participant.
+	builder func(id uint64, quorum int) api.ReplicaInstance
+
 	// commandSet is used to record the commands' waiting list according to receive order.
 	commandSet types.CommandSet
 
@@ -71,15 +98,31 @@ type metaPool struct {
 	// clients are used to track the commands send from them.
 	clients map[uint64]api.ClientInstance
 
+	// notifier is used to notify the clients whose missing commands have been skipped.
+	notifier external.ClientService
+
 	// active indicates the number of active client instance.
 	active *int64
 
 	// commandC is used to receive the valid transaction from one client instance.
 	commandC chan *types.CommandIndex
 
+	// overload is the policy for client instances to process the commands once commandC is full.
+	overload string
+
 	// closeC is used to stop log manager.
 	closeC chan bool
 
+	// ctx is the lifecycle context of log manager, which would be canceled once we stop it,
+	// so that the readers waiting for commands or partial orders could be released.
+	ctx context.Context
+
+	// cancel is used to cancel the lifecycle context.
+	cancel context.CancelFunc
+
+	// workers is used to wait for the goroutines of meta pool before we close the write-ahead log.
+	workers sync.WaitGroup
+
 	//=================================== local timer service ========================================
 
 	// timer is used to control the timeout event to generate order with commands in waiting list.
@@ -88,16 +131,74 @@ type metaPool struct {
 	// timeoutC is used to receive timeout event.
 	timeoutC <-chan bool
 
+	// batch is used to decide when the commands in waiting list should be selected into a pre-order.
+	batch api.BatchPolicy
+
+	// voteTimeout is the interval to wait for quorum votes before we retransmit a pre-order,
+	// and a non-positive one means the retransmission is disabled.
+	voteTimeout time.Duration
+
+	// retransmitC is used to receive the digest of pre-order whose retransmission timer has expired.
+	retransmitC chan string
+
+	// retransmits records the number of retransmissions for the pre-orders in aggMap since their latest new vote.
+	retransmits map[string]int
+
 	//======================================= consensus manager ============================================
 
 	// commitNo indicates the maximum committed number for each participant's partial order.
 	commitNo map[uint64]uint64
 
+	//======================================= fetch missing ============================================
+
+	// fetchTimeout is the interval to wait for a committed message before we fetch it from others.
+	fetchTimeout time.Duration
+
+	// gapTimeout is the interval to wait for the missing command which stalls a client before we skip it,
+	// and a non-positive one means we would never skip the missing commands.
+	gapTimeout time.Duration
+
+	// fetchMutex is used to protect the outstanding fetch requests for commands.
+	fetchMutex sync.Mutex
+
+	// fetching records the digests of commands we are fetching from others, and only the returned commands
+	// we have requested would be recorded, so that others could not fill our command tracker with unsolicited ones.
+	fetching map[string]struct{}
+
+	// gaps records the sequence numbers of missing commands we are fetching for the stalled clients.
+	gaps map[uint64]uint64
+
+	// missingC is used to receive the returned commands which stall the clients. The gaps are resolved on
+	// their own goroutine, since the commands released by them are proposed towards commandC, which may block
+	// until the main loop of meta pool drains it.
+	missingC chan *protos.Command
+
+	//======================================= checkpoint ============================================
+
+	// stable is the latest stable checkpoint, and we would like to garbage collect the states
+	// below it once we have received the next one, so that the lagging nodes could still fetch them.
+	stable types.Checkpoint
+
+	//======================================= write-ahead log ============================================
+
+	// wal is used to persist the pre-orders we have generated and the query streams we have committed.
+	wal api.WriteAheadLog
+
 	//==================================== crypto management =============================================
 
 	// crypto is used to generate/verify certificates.
 	crypto api.Crypto
 
+	// reporter is used to report the proofs of misbehavior.
+	reporter *misbehaviorReporter
+
+	// clientKeys are the public keys of registered clients, and the commands should be signed by their authors
+	// once there is any registered client.
+	clientKeys map[uint64]external.PublicKey
+
+	// hasher is used to calculate and check the digests of messages.
+	hasher types.Hasher
+
 	//======================================= external tools ===========================================
 
 	// sender is used to send consensus message into network.
@@ -110,24 +211,52 @@ type metaPool struct {
 	metrics *metrics.MetaPoolMetrics
 }
 
-func NewMetaPool(conf Config) api.MetaPool {
+func NewMetaPool(conf Config) (api.MetaPool, error) {
 	conf.Logger.Infof("[%d] initiate log manager, replica count %d", conf.Author, conf.N)
 
+	// initiate write-ahead log.
+	wLog, err := wal.NewWriteAheadLog(conf.Author, conf.WALPath, conf.Logger)
+	if err != nil {
+		return nil, err
+	}
+
 	// initiate communication channel.
-	commandC := make(chan *types.CommandIndex, 100)
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = types.DefaultQueueSize
+	}
+	commandC := make(chan *types.CommandIndex, conf.QueueSize)
 	timeoutC := make(chan bool)
 
+	// the participants of one cluster should use the same hasher.
+	if conf.Hasher == nil {
+		conf.Hasher = types.DefaultHasher()
+	}
+
 	// initiate committed number tracker.
 	committedTracker := make(map[uint64]uint64)
 
-	// initiate a partial tracker for current node.
+	// initiate trackers for current node.
 	pTracker := tracker.NewPartialTracker(conf.Author, conf.Logger)
+	cTracker := tracker.NewCommandTracker(conf.Author, conf.Readers, conf.CommandLimit, conf.Hasher, conf.Logger)
+
+	// initiate misbehavior reporter.
+	reporter := newMisbehaviorReporter(conf.Author, conf.Misbehavior, conf.Sender, conf.Logger)
+
+	// the window should contain one pre-order at least.
+	if conf.Window <= 0 {
+		conf.Window = 1
+	}
 
 	// initiate replica instances.
+	builder := func(id uint64, quorum int) api.ReplicaInstance {
+		return instance.NewReplicaInstance(conf.Author, id, quorum, conf.Window, conf.Timestamp, conf.Hasher, pTracker, cTracker, wLog, conf.Crypto, reporter, conf.Sender, conf.Logger, conf.Metrics)
+	}
+	members := make([]uint64, conf.N)
 	subs := make(map[uint64]api.ReplicaInstance)
 	for i := 0; i < conf.N; i++ {
 		id := uint64(i + 1)
-		subs[id] = instance.NewReplicaInstance(conf.Author, id, pTracker, conf.Crypto, conf.Sender, conf.Logger)
+		members[i] = id
+		subs[id] = builder(id, types.CalculateQuorum(conf.N))
 		committedTracker[id] = 0
 	}
 
@@ -135,74 +264,156 @@ func NewMetaPool(conf Config) api.MetaPool {
 	active := new(int64)
 	*active = int64(0)
 
+	// initiate lifecycle context.
+	ctx, cancel := context.WithCancel(context.Background())
+
 	// initiate client instances.
 	clients := make(map[uint64]api.ClientInstance)
 	for i := 0; i < conf.N*conf.Multi; i++ {
 		id := uint64(i + 1)
-		client := instance.NewClient(conf.Author, id, commandC, active, conf.Logger)
+		client := instance.NewClient(conf.Author, id, commandC, ctx.Done(), conf.Overload, active, conf.Logger)
 		clients[id] = client
 	}
 
-	return &metaPool{
-		author:   conf.Author,
-		n:        conf.N,
-		multi:    conf.Multi,
-		quorum:   types.CalculateQuorum(conf.N),
-		sequence: uint64(0),
-		aggMap:   make(map[string]*protos.PartialOrder),
-		replicas: subs,
-		pTracker: pTracker,
-		cTracker: tracker.NewCommandTracker(conf.Author, conf.Logger),
-		clients:  clients,
-		commandC: commandC,
-		timer:    newLocalTimer(conf.Author, timeoutC, conf.Duration, conf.Logger),
-		timeoutC: timeoutC,
-		closeC:   make(chan bool),
-		crypto:   conf.Crypto,
-		sender:   conf.Sender,
-		logger:   conf.Logger,
-		metrics:  conf.Metrics,
-		commitNo: committedTracker,
-		active:   active,
-		byz:      conf.Byz,
+	mp := &metaPool{
+		author:       conf.Author,
+		n:            conf.N,
+		epoch:        uint64(0),
+		members:      members,
+		decoder:      conf.KeyDecoder,
+		multi:        conf.Multi,
+		quorum:       types.CalculateQuorum(conf.N),
+		sequence:     uint64(0),
+		window:       conf.Window,
+		aggMap:       make(map[string]*protos.PartialOrder),
+		replicas:     subs,
+		pTracker:     pTracker,
+		builder:      builder,
+		cTracker:     cTracker,
+		clients:      clients,
+		notifier:     conf.Clients,
+		commandC:     commandC,
+		overload:     conf.Overload,
+		timer:        newLocalTimer(conf.Author, timeoutC, conf.Batch.Delay(), conf.Logger),
+		timeoutC:     timeoutC,
+		batch:        conf.Batch,
+		voteTimeout:  conf.VoteTimeout,
+		retransmitC:  make(chan string),
+		retransmits:  make(map[string]int),
+		closeC:       make(chan bool),
+		ctx:          ctx,
+		cancel:       cancel,
+		crypto:       conf.Crypto,
+		reporter:     reporter,
+		clientKeys:   conf.ClientKeys,
+		hasher:       conf.Hasher,
+		sender:       conf.Sender,
+		logger:       conf.Logger,
+		metrics:      conf.Metrics,
+		commitNo:     committedTracker,
+		active:       active,
+		byz:          conf.Byz,
+		fetchTimeout: conf.FetchTimeout,
+		gapTimeout:   conf.GapTimeout,
+		fetching:     make(map[string]struct{}),
+		gaps:         make(map[uint64]uint64),
+		missingC:     make(chan *protos.Command),
+		wal:          wLog,
 		//snapping: true,
 		//first:    true,
 	}
+
+	// recover the status of meta pool with write-ahead log.
+	if err := mp.replay(); err != nil {
+		return nil, fmt.Errorf("replay write-ahead log failed: %s", err)
+	}
+
+	// the recovered partial orders below the committed numbers would never be read by executor.
+	watermarks := make(map[uint64]uint64, len(mp.commitNo))
+	for id, no := range mp.commitNo {
+		watermarks[id] = no
+	}
+	mp.pTracker.Checkpoint(watermarks)
+
+	return mp, nil
 }
 
 func (mp *metaPool) Run() {
+	if !mp.startWorker() {
+		return
+	}
+	defer mp.workers.Done()
+
+	// the pre-orders recovered from write-ahead log may not have collected quorum votes yet.
+	mp.rebroadcastPreOrders()
+
+	// the clients stalled by missing commands are checked periodically.
+	if mp.fetchTimeout > 0 && mp.startWorker() {
+		go mp.resolveGaps()
+	}
+
 	for {
 		select {
 		case <-mp.closeC:
 			return
 		case c := <-mp.commandC:
-			mp.appendCommandIndex(c)
+			if err := mp.appendCommandIndex(c); err != nil {
+				panic(fmt.Sprintf("log manager runtime error: %s", err))
+			}
 		case <-mp.timeoutC:
 			if err := mp.tryGeneratePreOrder(); err != nil {
 				panic(fmt.Sprintf("log manager runtime error: %s", err))
 			}
+		case digest := <-mp.retransmitC:
+			mp.retransmitPreOrder(digest)
 		}
 	}
 }
 
 func (mp *metaPool) Quit() {
 	mp.timer.stopTimer()
-	select {
-	case <-mp.closeC:
-	default:
-		close(mp.closeC)
+
+	mp.mutex.Lock()
+	if mp.ctx.Err() != nil {
+		// meta pool has been closed.
+		mp.mutex.Unlock()
+		return
+	}
+	mp.cancel()
+	close(mp.closeC)
+	mp.mutex.Unlock()
+
+	// the goroutines of meta pool may still append into write-ahead log, wait for them before we close it.
+	mp.workers.Wait()
+
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+	if err := mp.wal.Close(); err != nil {
+		mp.logger.Errorf("[%d] close write-ahead log failed: %s", mp.author, err)
+	}
+}
+
+// startWorker registers a goroutine of meta pool, and it returns false once meta pool has been closed.
+func (mp *metaPool) startWorker() bool {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	if mp.ctx.Err() != nil {
+		return false
 	}
+	mp.workers.Add(1)
+	return true
 }
 
 func (mp *metaPool) Committed(author uint64, seqNo uint64) {
-	mp.clients[author].Commit(seqNo)
+	mp.getClient(author).Commit(seqNo)
 }
 
 //===============================================================
 //                 Processor for Local Logs
 //===============================================================
 
-func (mp *metaPool) ProcessCommand(command *protos.Command) {
+func (mp *metaPool) ProcessCommand(command *protos.Command) error {
 	if mp.first {
 		if mp.author == uint64(2) {
 			// do nothing.
@@ -214,35 +425,90 @@ func (mp *metaPool) ProcessCommand(command *protos.Command) {
 	}
 	mp.first = false
 
+	// the author and sequence number of command should be authenticated before we record it with client instance,
+	// so that no one could inject commands for others or jam the client instance with fake sequence numbers.
+	if err := mp.verifyCommand(command); err != nil {
+		mp.metrics.RejectCommand()
+		return fmt.Errorf("invalid command %s: %w", command.Format(), err)
+	}
+
+	// record the command with command tracker, which would validate the size and content of it.
+	if err := mp.cTracker.RecordCommand(command); err != nil {
+		if errors.Is(err, types.ErrClientEquivocation) {
+			mp.reportEquivocation(command)
+		}
+		mp.metrics.RejectCommand()
+		return fmt.Errorf("invalid command %s: %w", command.Format(), err)
+	}
+
 	// record metrics.
 	mp.metrics.ProcessCommand()
 
-	// record the command with command tracker.
-	mp.cTracker.RecordCommand(command)
-
 	if mp.byz && mp.snapping && command.Author != mp.author {
 		// current node is the arbitrary
 		// it is in snapping up situation.
-		return
+		return nil
 	}
 
 	// select the client instance and record the command target.
-	mp.clientInstanceReminder(command)
+	return mp.clientInstanceReminder(command)
 }
 
-func (mp *metaPool) clientInstanceReminder(command *protos.Command) {
-	// select the client.
-	client, ok := mp.clients[command.Author]
+// verifyCommand checks the signature of command with the public key of its author.
+func (mp *metaPool) verifyCommand(command *protos.Command) error {
+	if len(mp.clientKeys) == 0 {
+		// the client authentication is disabled.
+		return nil
+	}
+
+	key, ok := mp.clientKeys[command.Author]
 	if !ok {
-		// if there is not a client instance, initiate it.
-		// NOTE: concurrency problem.
-		mp.logger.Errorf("[%d] don't have client instance %d, initiate it", mp.author, command.Author)
-		client = instance.NewClient(mp.author, command.Author, mp.commandC, mp.active, mp.logger)
-		mp.clients[command.Author] = client
+		return fmt.Errorf("unregistered client %d", command.Author)
+	}
+
+	if command.Signature == nil {
+		return fmt.Errorf("nil signature")
+	}
+
+	// the signature is generated on digest, and the content would be validated by command tracker.
+	if err := types.CheckCommandDigest(mp.hasher, command); err != nil {
+		return err
+	}
+
+	if err := key.Verify(command.Signature, types.StringToBytes(command.Digest)); err != nil {
+		return fmt.Errorf("invalid signature: %s", err)
 	}
+	return nil
+}
+
+func (mp *metaPool) clientInstanceReminder(command *protos.Command) error {
+	// select the client.
+	client := mp.getClient(command.Author)
 
 	// append the transaction into this client.
-	client.Append(command)
+	_, err := client.Append(command)
+	return err
+}
+
+// getClient returns the client instance, and it would be initiated if there is not one.
+func (mp *metaPool) getClient(id uint64) api.ClientInstance {
+	mp.mutex.RLock()
+	client, ok := mp.clients[id]
+	mp.mutex.RUnlock()
+	if ok {
+		return client
+	}
+
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	if client, ok = mp.clients[id]; !ok {
+		// if there is not a client instance, initiate it.
+		mp.logger.Errorf("[%d] don't have client instance %d, initiate it", mp.author, id)
+		client = instance.NewClient(mp.author, id, mp.commandC, mp.ctx.Done(), mp.overload, mp.active, mp.logger)
+		mp.clients[id] = client
+	}
+	return client
 }
 
 func (mp *metaPool) checkHighOrder() error {
@@ -271,16 +537,31 @@ func (mp *metaPool) updateHighOrder(pre *protos.PreOrder) {
 }
 
 // appendCommandIndex is used to append the received command index into the command set.
-func (mp *metaPool) appendCommandIndex(cIndex *types.CommandIndex) {
+func (mp *metaPool) appendCommandIndex(cIndex *types.CommandIndex) error {
 	mp.mutex.Lock()
 	defer mp.mutex.Unlock()
 
+	mp.batch.ObserveArrival()
+
 	if len(mp.commandSet) == 0 {
-		mp.timer.startTimer()
+		mp.startBatchTimer()
 	}
 
 	// command list with receive-order.
 	mp.commandSet = append(mp.commandSet, cIndex)
+
+	if maxSize := mp.batch.MaxSize(); maxSize > 0 && len(mp.commandSet) >= maxSize {
+		// the waiting list is full, generate pre-order at once.
+		mp.logger.Debugf("[%d] waiting list reached max batch size %d", mp.author, maxSize)
+		return mp.generateOrder()
+	}
+	return nil
+}
+
+// startBatchTimer starts the timer to wait for more commands with the delay given by batching policy.
+func (mp *metaPool) startBatchTimer() {
+	mp.timer.updateDuration(mp.batch.Delay())
+	mp.timer.startTimer()
 }
 
 // tryGeneratePreOrder is used to process the command received from one client instance.
@@ -300,6 +581,17 @@ func (mp *metaPool) generateOrder() error {
 		return nil
 	}
 
+	if mp.ctx.Err() != nil {
+		// meta pool has been closed, and the write-ahead log may have been closed too.
+		return nil
+	}
+
+	if len(mp.aggMap) >= mp.window {
+		// the window is full, the commands would be selected once one of the pre-orders has been verified.
+		mp.logger.Debugf("[%d] pipeline window is full, %d pre-orders in flight", mp.author, len(mp.aggMap))
+		return nil
+	}
+
 	// make sure the highest partial order has a valid status.
 	if err := mp.checkHighOrder(); err != nil {
 		return fmt.Errorf("highest partial order error: %s", err)
@@ -308,10 +600,19 @@ func (mp *metaPool) generateOrder() error {
 	// advance the sequence number.
 	mp.sequence++
 
+	// select the commands with the earliest timestamps, and the others would wait for the next pre-order.
+	sort.Sort(mp.commandSet)
+	var remaining types.CommandSet
+	if maxSize := mp.batch.MaxSize(); maxSize > 0 && len(mp.commandSet) > maxSize {
+		remaining = append(remaining, mp.commandSet[maxSize:]...)
+		mp.commandSet = mp.commandSet[:maxSize]
+	}
+
 	digestList := make([]string, len(mp.commandSet))
 	timestampList := make([]int64, len(mp.commandSet))
+	clientList := make([]uint64, len(mp.commandSet))
+	clientSeqList := make([]uint64, len(mp.commandSet))
 
-	sort.Sort(mp.commandSet)
 	if mp.byz && !mp.snapping {
 		// current node is the arbitrary, and it's not snapping up situation.
 		timeSet := make([]int64, len(mp.commandSet))
@@ -329,24 +630,41 @@ func (mp *metaPool) generateOrder() error {
 			mp.commandSet = append(mp.commandSet, command)
 		}
 	}
+	// the timestamps should be non-decreasing along our chain, or the others would refuse to vote for it.
+	previous := int64(0)
+	if mp.highOrder != nil && len(mp.highOrder.TimestampList) > 0 {
+		previous = mp.highOrder.TimestampList[len(mp.highOrder.TimestampList)-1]
+	}
+	for _, cIndex := range mp.commandSet {
+		if cIndex.OTime < previous {
+			cIndex.OTime = previous
+		}
+		previous = cIndex.OTime
+	}
+
 	for i, cIndex := range mp.commandSet {
 		digestList[i] = cIndex.Digest
 		timestampList[i] = cIndex.OTime
+		clientList[i] = cIndex.Author
+		clientSeqList[i] = cIndex.SeqNo
 
 		// record metrics.
 		mp.metrics.SelectCommand(cIndex)
 	}
 
 	// generate pre order message.
-	pre := protos.NewPreOrder(mp.author, mp.sequence, digestList, timestampList, mp.highOrder)
-	digest, err := types.CalculateDigest(pre)
+	pre := protos.NewPreOrder(mp.author, mp.sequence, digestList, timestampList, clientList, clientSeqList, mp.highOrder)
+	digest, err := types.CalculateDigest(mp.hasher, pre)
 	if err != nil {
 		return fmt.Errorf("pre order marshal error: %s", err)
 	}
 	pre.Digest = digest
 
 	// reset receive-order lists.
-	mp.commandSet = nil
+	mp.commandSet = remaining
+	if len(mp.commandSet) > 0 {
+		mp.startBatchTimer()
+	}
 
 	// generate self-signature for current pre-order
 	signature, err := mp.crypto.PrivateSign(types.StringToBytes(pre.Digest))
@@ -354,9 +672,21 @@ func (mp *metaPool) generateOrder() error {
 		return fmt.Errorf("generate signature for pre-order failed: %s", err)
 	}
 
+	// the self-signature is also used by others to prove our pre-order.
+	pre.Signature = signature
+
+	// persist the pre-order before we send it, so that we won't generate a conflicting pre-order after a restart.
+	if err := mp.wal.Append(&protos.WALEntry{Type: protos.WALEntryType_WAL_PRE_ORDER, PreOrder: pre}); err != nil {
+		return fmt.Errorf("persist pre-order failed: %s", err)
+	}
+
 	// init the order message in aggregate map and assign self signature
 	mp.aggMap[pre.Digest] = protos.NewPartialOrder(pre)
-	mp.aggMap[pre.Digest].QC.Certs[mp.author] = signature
+	mp.aggMap[pre.Digest].QC.AddCert(mp.author, signature)
+
+	// the ordered time records the generation of pre-order until it has collected quorum votes,
+	// so that we could observe the quorum latency for batching policy.
+	mp.aggMap[pre.Digest].SetOrderedTime()
 
 	mp.logger.Infof("[%d] generate pre-order %s", mp.author, pre.Format())
 
@@ -369,11 +699,72 @@ func (mp *metaPool) generateOrder() error {
 	}
 	mp.sender.BroadcastPCM(cm)
 
+	// the votes may be lost, and we would retransmit the pre-order if it couldn't collect quorum votes in time.
+	mp.startRetransmitTimer(pre.Digest)
+
 	// record metrics.
 	mp.metrics.GenerateOrder()
 	return nil
 }
 
+// startRetransmitTimer starts the retransmission timer for the pre-order waiting for quorum votes,
+// and the interval is doubled with each retransmission.
+func (mp *metaPool) startRetransmitTimer(digest string) {
+	if mp.voteTimeout <= 0 {
+		return
+	}
+	delay := mp.voteTimeout << mp.retransmits[digest]
+
+	f := func() {
+		select {
+		case mp.retransmitC <- digest:
+		case <-mp.closeC:
+		}
+	}
+	time.AfterFunc(delay, f)
+}
+
+// retransmitPreOrder is used to send the pre-order to the participants who haven't voted for it yet,
+// and they would vote for it, or re-send the vote they have generated.
+func (mp *metaPool) retransmitPreOrder(digest string) {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	pOrder, ok := mp.aggMap[digest]
+	if !ok {
+		// the pre-order has collected quorum votes.
+		delete(mp.retransmits, digest)
+		return
+	}
+
+	if mp.retransmits[digest] >= types.MaxRetransmission {
+		// the participants refuse to vote for it, stop retransmission until we receive a new vote or restart.
+		mp.logger.Errorf("[%d] pre-order %s cannot collect quorum votes after %d retransmissions", mp.author, pOrder.PreOrder.Format(), mp.retransmits[digest])
+		return
+	}
+	mp.retransmits[digest]++
+
+	cm, err := protos.PackPreOrder(pOrder.PreOrder)
+	if err != nil {
+		mp.logger.Errorf("[%d] generate consensus message error: %s", mp.author, err)
+		return
+	}
+
+	voted := make(map[uint64]bool)
+	for _, entry := range pOrder.QC.Certs {
+		voted[entry.ID] = true
+	}
+	for _, id := range mp.members {
+		if voted[id] {
+			continue
+		}
+		mp.logger.Infof("[%d] retransmit pre-order %s to node %d", mp.author, pOrder.PreOrder.Format(), id)
+		mp.sender.UnicastPCM(protos.NewConsensusMessage(cm.Type, cm.From, id, cm.Payload))
+	}
+
+	mp.startRetransmitTimer(digest)
+}
+
 // ProcessVote is used to process the vote message from others.
 // It could aggregate a agg-signature for one pre-order and generate an order message for one command.
 func (mp *metaPool) ProcessVote(vote *protos.Vote) error {
@@ -398,27 +789,61 @@ func (mp *metaPool) ProcessVote(vote *protos.Vote) error {
 	}
 
 	// record the certification in current vote
-	pOrder.QC.Certs[vote.Author] = vote.Certification
+	if !pOrder.QC.AddCert(vote.Author, vote.Certification) {
+		// a duplicated vote re-sent by the signer we have recorded, which cannot help us to collect quorum votes.
+		return nil
+	}
+
+	// the participants are still voting, so that the retransmission starts over, and the stopped one is restarted.
+	stopped := mp.retransmits[vote.Digest] >= types.MaxRetransmission
+	mp.retransmits[vote.Digest] = 0
+	if stopped {
+		mp.startRetransmitTimer(vote.Digest)
+	}
 
 	// check the quorum size for proof-certs
-	if len(pOrder.QC.Certs) == mp.quorum {
-		pOrder.SetOrderedTime()
+	if len(pOrder.QC.Certs) >= mp.quorum {
+		if err := mp.quorumOrder(pOrder); err != nil {
+			return err
+		}
+
+		// there is room in the window now, and the commands waiting for it could be selected.
+		return mp.generateOrder()
+	}
 
-		mp.logger.Debugf("[%d] found quorum votes, generate quorum order %s", mp.author, pOrder.Format())
-		delete(mp.aggMap, vote.Digest)
+	mp.logger.Debugf("[%d] aggregate vote for %s, need %d, has %d", mp.author, pOrder.PreOrderDigest(), mp.quorum, len(pOrder.QC.Certs))
+	return nil
+}
 
-		cm, err := protos.PackPartialOrder(pOrder)
+// quorumOrder generates the partial order for our pre-order which has collected quorum votes.
+func (mp *metaPool) quorumOrder(pOrder *protos.PartialOrder) error {
+	// compress the quorum-cert, so that the partial order and the proposals referring to it
+	// only carry one aggregated signature.
+	if mp.crypto.Aggregated() {
+		qc, err := mp.crypto.Aggregate(types.StringToBytes(pOrder.PreOrderDigest()), pOrder.QC.Certs)
 		if err != nil {
-			return fmt.Errorf("generate consensus message error: %s", err)
+			return fmt.Errorf("failed to aggregate: %s", err)
 		}
-		mp.sender.BroadcastPCM(cm)
+		pOrder.QC = qc
+	}
+	generated := pOrder.OrderedTime
+	pOrder.SetOrderedTime()
+	if generated != 0 {
+		mp.batch.ObserveQuorum(time.Duration(pOrder.OrderedTime - generated))
+	}
 
-		// record metrics.
-		mp.metrics.PartialOrderQuorum(pOrder)
-		return nil
+	mp.logger.Debugf("[%d] found quorum votes, generate quorum order %s", mp.author, pOrder.Format())
+	delete(mp.aggMap, pOrder.PreOrderDigest())
+	delete(mp.retransmits, pOrder.PreOrderDigest())
+
+	cm, err := protos.PackPartialOrder(pOrder)
+	if err != nil {
+		return fmt.Errorf("generate consensus message error: %s", err)
 	}
+	mp.sender.BroadcastPCM(cm)
 
-	mp.logger.Debugf("[%d] aggregate vote for %s, need %d, has %d", mp.author, pOrder.PreOrderDigest(), mp.quorum, len(pOrder.QC.Certs))
+	// record metrics.
+	mp.metrics.PartialOrderQuorum(pOrder)
 	return nil
 }
 
@@ -431,7 +856,11 @@ func (mp *metaPool) ProcessVote(vote *protos.Vote) error {
 // whose sequence number is the same as it yet, and we would like to generate a
 // vote message for it if it's legal for us.
 func (mp *metaPool) ProcessPreOrder(pre *protos.PreOrder) error {
-	return mp.replicas[pre.Author].ReceivePreOrder(pre)
+	replica, ok := mp.getReplica(pre.Author)
+	if !ok {
+		return fmt.Errorf("cannot find replica instance for node %d", pre.Author)
+	}
+	return replica.ReceivePreOrder(pre)
 }
 
 // ProcessPartial is used to process quorum-cert messages.
@@ -439,41 +868,69 @@ func (mp *metaPool) ProcessPreOrder(pre *protos.PreOrder) error {
 // could advance the sequence counter. We should record the advanced counter and put the info of
 // order message into the sequential-pool.
 func (mp *metaPool) ProcessPartial(pOrder *protos.PartialOrder) error {
-	return mp.replicas[pOrder.Author()].ReceivePartial(pOrder)
+	replica, ok := mp.getReplica(pOrder.Author())
+	if !ok {
+		return fmt.Errorf("cannot find replica instance for node %d", pOrder.Author())
+	}
+	return replica.ReceivePartial(pOrder)
+}
+
+// getReplica returns the replica instance for participant in current membership.
+func (mp *metaPool) getReplica(id uint64) (api.ReplicaInstance, bool) {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+
+	replica, ok := mp.replicas[id]
+	return replica, ok
 }
 
 //===============================================================
 //                   Read Essential Info
 //===============================================================
 
-func (mp *metaPool) ReadCommand(commandD string) *protos.Command {
-	command := mp.cTracker.ReadCommand(commandD)
+func (mp *metaPool) ReadCommand(commandD string, referrers []uint64) *protos.Command {
+	return mp.readCommand(commandD, referrers, mp.cTracker.WaitCommand)
+}
+
+func (mp *metaPool) PeekCommand(commandD string, referrers []uint64) *protos.Command {
+	return mp.readCommand(commandD, referrers, mp.cTracker.PeekCommand)
+}
 
+// readCommand waits for the command with the wait function of command tracker, and fetches it from the referrers
+// if we haven't received it in time.
+func (mp *metaPool) readCommand(commandD string, referrers []uint64, wait func(ctx context.Context, digest string) *protos.Command) *protos.Command {
 	for {
+		ctx, cancel := context.WithTimeout(mp.ctx, mp.fetchTimeout)
+		command := wait(ctx, commandD)
+		cancel()
+
 		if command != nil {
-			break
+			mp.fetchMutex.Lock()
+			delete(mp.fetching, commandD)
+			mp.fetchMutex.Unlock()
+			return command
 		}
 
-		// if we could not read the command, just try the next time.
-		command = mp.cTracker.ReadCommand(commandD)
-	}
+		if mp.ctx.Err() != nil {
+			// meta pool has been closed.
+			return nil
+		}
 
-	return command
+		// the command has been selected to commit, but we still haven't received it,
+		// try to fetch it from the replicas which have referred to it.
+		mp.fetchCommand(commandD, referrers)
+	}
 }
 
 func (mp *metaPool) ReadPartials(qStream types.QueryStream) []*protos.PartialOrder {
 	var res []*protos.PartialOrder
 
 	for _, qIndex := range qStream {
-		pOrder := mp.pTracker.ReadPartial(qIndex)
-
-		for {
-			if pOrder != nil {
-				break
-			}
+		pOrder := mp.readPartial(qIndex)
 
-			// if we could not read the partial order, just try the next time.
-			pOrder = mp.pTracker.ReadPartial(qIndex)
+		if pOrder == nil {
+			// meta pool has been closed.
+			return nil
 		}
 
 		res = append(res, pOrder)
@@ -482,86 +939,853 @@ func (mp *metaPool) ReadPartials(qStream types.QueryStream) []*protos.PartialOrd
 	return res
 }
 
-//=====================================================================
-//                  Consensus Proposal Manager
-//=====================================================================
-
-func (mp *metaPool) GenerateProposal() (*protos.PartialOrderBatch, error) {
-	batch := protos.NewPartialOrderBatch(mp.author, mp.n)
-
-	for id, replica := range mp.replicas {
-		index := int(id - 1)
+func (mp *metaPool) readPartial(qIndex types.QueryIndex) *protos.PartialOrder {
+	for {
+		ctx, cancel := context.WithTimeout(mp.ctx, mp.fetchTimeout)
+		pOrder := mp.pTracker.WaitPartial(ctx, qIndex)
+		cancel()
 
-		// read the highest partial order from replica 'id'.
-		hOrder := replica.GetHighOrder()
+		if pOrder != nil {
+			return pOrder
+		}
 
-		if hOrder == nil {
-			// high-order for replica 'id' is nil, record 0 in batch tracker.
-			batch.HighOrders[index] = protos.NewNopPartialOrder()
-			batch.SeqList[index] = 0
-			continue
+		if mp.ctx.Err() != nil {
+			// meta pool has been closed.
+			return nil
 		}
 
-		// update batch tracker with information of high-order.
-		batch.HighOrders[index] = hOrder
-		batch.SeqList[index] = hOrder.Sequence()
+		// the partial order has been committed by consensus, but we still haven't received it,
+		// try to fetch it from others.
+		mp.fetchPartial(qIndex)
 	}
-
-	mp.logger.Debugf("[%d] generate batch %s", mp.author, batch.Format())
-	return batch, nil
 }
 
-func (mp *metaPool) VerifyProposal(batch *protos.PartialOrderBatch) (types.QueryStream, error) {
-	updated := false
-
-	for index, no := range batch.SeqList {
+//===============================================================
+//                   Fetch Missing Process
+//===============================================================
 
-		// calculate the node id.
-		id := uint64(index + 1)
+// fetchPartial is used to request the missing partial order from the others.
+func (mp *metaPool) fetchPartial(qIndex types.QueryIndex) {
+	fetch := &protos.FetchPartial{Author: mp.author, Generator: qIndex.Author, Sequence: qIndex.SeqNo}
+	mp.logger.Infof("[%d] fetch missing partial order %s", mp.author, fetch.Format())
 
-		if no <= mp.commitNo[id] {
-			// committed previous partial order for node id, including partial number 0.
-			mp.logger.Debugf("[%d] haven't updated committed partial order for node %d", mp.author, id)
-			continue
-		}
+	cm, err := protos.PackFetchPartial(fetch)
+	if err != nil {
+		mp.logger.Errorf("[%d] generate consensus message error: %s", mp.author, err)
+		return
+	}
+	mp.sender.BroadcastPCM(cm)
+}
 
-		pOrder := batch.HighOrders[index]
+// ProcessFetchPartial is used to process the request from others to fetch a partial order
+// which has been committed but hasn't been received by the requester.
+func (mp *metaPool) ProcessFetchPartial(fetch *protos.FetchPartial) error {
+	if fetch.Author == mp.author {
+		// ignore the fetch request generated by ourselves.
+		return nil
+	}
 
-		if pOrder.Sequence() != no {
-			return nil, fmt.Errorf("invalid partial order seqNo, proposedNo %d, partial seqNo %d", no, pOrder.Sequence())
-		}
+	pOrder := mp.pTracker.GetPartial(types.NewQueryIndex(fetch.Generator, fetch.Sequence))
+	if pOrder == nil {
+		mp.logger.Debugf("[%d] cannot find partial order for %s", mp.author, fetch.Format())
+		return nil
+	}
 
-		qIndex := types.QueryIndex{Author: pOrder.Author(), SeqNo: pOrder.Sequence()}
-		if !mp.pTracker.IsExist(qIndex) {
-			if err := mp.crypto.VerifyProofCerts(types.StringToBytes(pOrder.PreOrderDigest()), pOrder.QC, mp.quorum); err != nil {
-				return nil, fmt.Errorf("invalid high partial order received from %d: %s", batch.Author, err)
-			}
-		}
+	mp.logger.Debugf("[%d] return partial order %s to node %d", mp.author, pOrder.Format(), fetch.Author)
+	cm, err := protos.PackReturnPartial(pOrder, mp.author, fetch.Author)
+	if err != nil {
+		return fmt.Errorf("generate consensus message error: %s", err)
+	}
+	mp.sender.UnicastPCM(cm)
+	return nil
+}
 
-		updated = true
+// ProcessReturnPartial is used to process the partial order returned by others in fetch-missing process.
+func (mp *metaPool) ProcessReturnPartial(pOrder *protos.PartialOrder) error {
+	if pOrder.PreOrder == nil {
+		return fmt.Errorf("nil pre-order in returned partial order")
 	}
 
-	if !updated {
-		// haven't updated committed partial order.
-		return nil, nil
+	if mp.pTracker.IsExist(types.NewQueryIndex(pOrder.Author(), pOrder.Sequence())) {
+		// we have already received it.
+		return nil
 	}
 
-	var qStream types.QueryStream
+	replica, ok := mp.getReplica(pOrder.Author())
+	if !ok {
+		return fmt.Errorf("cannot find replica instance for node %d", pOrder.Author())
+	}
+	return replica.ReceiveFetchedPartial(pOrder)
+}
+
+// fetchCommand is used to request the missing command from the replicas which have referred to it.
+func (mp *metaPool) fetchCommand(commandD string, referrers []uint64) {
+	fetch := &protos.FetchCommand{Author: mp.author, Digest: commandD}
+	mp.logger.Infof("[%d] fetch missing command %s from %v", mp.author, fetch.Format(), referrers)
+
+	mp.fetchMutex.Lock()
+	mp.fetching[commandD] = struct{}{}
+	mp.fetchMutex.Unlock()
+
+	for _, id := range referrers {
+		if id == mp.author {
+			// we cannot fetch the missing command from ourselves.
+			continue
+		}
+
+		cm, err := protos.PackFetchCommand(fetch, id)
+		if err != nil {
+			mp.logger.Errorf("[%d] generate consensus message error: %s", mp.author, err)
+			return
+		}
+		mp.sender.UnicastPCM(cm)
+	}
+}
+
+// ProcessFetchCommand is used to process the request from others to fetch a command
+// which has been referred by our partial orders, or which stalls one of their clients.
+func (mp *metaPool) ProcessFetchCommand(fetch *protos.FetchCommand) error {
+	if fetch.Author == mp.author {
+		// ignore the fetch request generated by ourselves.
+		return nil
+	}
+
+	var command *protos.Command
+	if fetch.Digest == "" {
+		command = mp.cTracker.GetCommandByIndex(types.QueryIndex{Author: fetch.Client, SeqNo: fetch.Sequence})
+	} else {
+		command = mp.cTracker.GetCommand(fetch.Digest)
+	}
+	if command == nil {
+		mp.logger.Debugf("[%d] cannot find command for %s", mp.author, fetch.Format())
+		return nil
+	}
+
+	mp.logger.Debugf("[%d] return command %s to node %d", mp.author, command.Format(), fetch.Author)
+	cm, err := protos.PackReturnCommand(command, mp.author, fetch.Author)
+	if err != nil {
+		return fmt.Errorf("generate consensus message error: %s", err)
+	}
+	mp.sender.UnicastPCM(cm)
+	return nil
+}
+
+// ProcessReturnCommand is used to process the command returned by others in fetch-missing process.
+// We only record it in command tracker to fulfill the blocks, instead of proposing it with our pre-orders,
+// unless it is the missing command which stalls the client.
+func (mp *metaPool) ProcessReturnCommand(command *protos.Command) error {
+	mp.logger.Debugf("[%d] received returned command %s", mp.author, command.Format())
+
+	// the returned command is relayed by another replica, so that it should be authenticated as the one
+	// received from client before we record it.
+	if err := mp.verifyCommand(command); err != nil {
+		mp.metrics.RejectCommand()
+		return fmt.Errorf("invalid returned command %s: %w", command.Format(), err)
+	}
+
+	requested, missing := mp.isFetching(command)
+	if !requested && !missing {
+		mp.logger.Debugf("[%d] ignore unsolicited command %s", mp.author, command.Format())
+		return nil
+	}
+
+	if err := mp.cTracker.RecordCommand(command); err != nil {
+		if errors.Is(err, types.ErrClientEquivocation) {
+			mp.reportEquivocation(command)
+		}
+		return fmt.Errorf("invalid returned command %s: %w", command.Format(), err)
+	}
+
+	mp.fetchMutex.Lock()
+	delete(mp.fetching, command.Digest)
+	if missing {
+		delete(mp.gaps, command.Author)
+	}
+	mp.fetchMutex.Unlock()
+
+	if !missing {
+		return nil
+	}
+
+	mp.mutex.RLock()
+	client, ok := mp.clients[command.Author]
+	mp.mutex.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	if seqNo, _, stalled := client.Gap(); stalled && seqNo == command.Sequence {
+		mp.logger.Infof("[%d] received missing command %s", mp.author, command.Format())
+		select {
+		case mp.missingC <- command:
+		case <-mp.closeC:
+		}
+	}
+	return nil
+}
+
+// isFetching returns if the command has been requested with its digest, and if it is the missing command
+// we have requested for a stalled client.
+func (mp *metaPool) isFetching(command *protos.Command) (requested bool, missing bool) {
+	mp.fetchMutex.Lock()
+	defer mp.fetchMutex.Unlock()
+
+	_, requested = mp.fetching[command.Digest]
+	seqNo, ok := mp.gaps[command.Author]
+	return requested, ok && seqNo == command.Sequence
+}
+
+// resolveGaps is used to check the stalled clients periodically and to append the missing commands we have fetched.
+func (mp *metaPool) resolveGaps() {
+	defer mp.workers.Done()
+
+	ticker := time.NewTicker(mp.fetchTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mp.closeC:
+			return
+		case <-ticker.C:
+			mp.checkGaps()
+		case command := <-mp.missingC:
+			if _, err := mp.getClient(command.Author).Append(command); err != nil {
+				mp.logger.Errorf("[%d] propose commands for client %d error: %s", mp.author, command.Author, err)
+			}
+		}
+	}
+}
+
+// checkGaps is used to process the clients stalled by missing commands. We would like to fetch the missing
+// command from others, and skip it once it has been missing for a long time and quorum replicas have moved past it.
+func (mp *metaPool) checkGaps() {
+	mp.mutex.RLock()
+	clients := make(map[uint64]api.ClientInstance, len(mp.clients))
+	for id, client := range mp.clients {
+		clients[id] = client
+	}
+	mp.mutex.RUnlock()
+
+	stalled := 0
+	gaps := make(map[uint64]uint64)
+	for id, client := range clients {
+		seqNo, since, ok := client.Gap()
+		if !ok || time.Since(since) < mp.fetchTimeout {
+			continue
+		}
+
+		if mp.gapTimeout > 0 && time.Since(since) >= mp.gapTimeout {
+			skipped, err := client.Skip(seqNo)
+			if err != nil {
+				mp.logger.Errorf("[%d] propose commands for client %d error: %s", mp.author, id, err)
+			}
+			if skipped {
+				mp.metrics.SkipCommand()
+				if mp.notifier != nil {
+					mp.notifier.ReportGap(id, seqNo)
+				}
+				continue
+			}
+		}
+
+		stalled++
+		gaps[id] = seqNo
+		mp.fetchMutex.Lock()
+		mp.gaps[id] = seqNo
+		mp.fetchMutex.Unlock()
+
+		fetch := &protos.FetchCommand{Author: mp.author, Client: id, Sequence: seqNo}
+		mp.logger.Infof("[%d] fetch missing command %s", mp.author, fetch.Format())
+		cm, err := protos.PackFetchCommand(fetch, 0)
+		if err != nil {
+			mp.logger.Errorf("[%d] generate consensus message error: %s", mp.author, err)
+			continue
+		}
+		mp.sender.BroadcastPCM(cm)
+	}
+
+	// the clients which are no longer stalled don't need the returned commands any more.
+	mp.fetchMutex.Lock()
+	for id := range mp.gaps {
+		if _, ok := gaps[id]; !ok {
+			delete(mp.gaps, id)
+		}
+	}
+	mp.fetchMutex.Unlock()
+
+	mp.metrics.UpdateStalledClients(stalled)
+}
+
+//=====================================================================
+//                  Consensus Proposal Manager
+//=====================================================================
+
+func (mp *metaPool) GenerateProposal() (*protos.PartialOrderBatch, error) {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+
+	batch := protos.NewPartialOrderBatch(mp.author, len(mp.members))
+	batch.Epoch = mp.epoch
+
+	for index, id := range mp.members {
+		// read the highest partial order from replica 'id'.
+		hOrder := mp.replicas[id].GetHighOrder()
+
+		if hOrder == nil {
+			// high-order for replica 'id' is nil, record 0 in batch tracker.
+			batch.HighOrders[index] = protos.NewNopPartialOrder()
+			batch.SeqList[index] = 0
+			continue
+		}
+
+		// update batch tracker with information of high-order.
+		batch.HighOrders[index] = hOrder
+		batch.SeqList[index] = hOrder.Sequence()
+	}
+
+	// propose the membership change for the next epoch.
+	if mp.pending != nil && mp.pending.Epoch == mp.epoch+1 {
+		batch.Reconfiguration = mp.pending
+	}
+
+	mp.logger.Debugf("[%d] generate batch %s", mp.author, batch.Format())
+	return batch, nil
+}
+
+func (mp *metaPool) VerifyProposal(batch *protos.PartialOrderBatch) (types.QueryStream, error) {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	if batch.Epoch != mp.epoch {
+		return nil, fmt.Errorf("invalid batch epoch, expect %d, received %d", mp.epoch, batch.Epoch)
+	}
+
+	if len(batch.SeqList) != len(mp.members) || len(batch.HighOrders) != len(mp.members) {
+		return nil, fmt.Errorf("invalid batch size, expect %d, received %d", len(mp.members), len(batch.SeqList))
+	}
+
+	// the batch has been consented by its digest, so that it should be encoded in canonical form.
+	if err := batch.CheckCanonical(); err != nil {
+		return nil, fmt.Errorf("invalid batch encoding: %s", err)
+	}
+
+	// the membership change should be authorized before we apply any status of current batch.
+	reconf := batch.Reconfiguration
+	if reconf != nil && reconf.Epoch != mp.epoch+1 {
+		reconf = nil
+	}
+	if reconf != nil {
+		if err := mp.verifyReconfiguration(reconf); err != nil {
+			return nil, fmt.Errorf("invalid reconfiguration: %s", err)
+		}
+	}
+
+	updated := false
 
 	for index, no := range batch.SeqList {
-		id := uint64(index + 1)
 
-		for {
-			if no <= mp.commitNo[id] {
-				break
+		// calculate the node id.
+		id := mp.members[index]
+
+		if no <= mp.commitNo[id] {
+			// committed previous partial order for node id, including partial number 0.
+			mp.logger.Debugf("[%d] haven't updated committed partial order for node %d", mp.author, id)
+			continue
+		}
+
+		pOrder := batch.HighOrders[index]
+
+		if pOrder.Sequence() != no {
+			return nil, fmt.Errorf("invalid partial order seqNo, proposedNo %d, partial seqNo %d", no, pOrder.Sequence())
+		}
+
+		if pOrder.Author() != id {
+			return nil, fmt.Errorf("invalid partial order author, expect %d, received %d", id, pOrder.Author())
+		}
+
+		qIndex := types.QueryIndex{Author: pOrder.Author(), SeqNo: pOrder.Sequence()}
+		if !mp.pTracker.IsExist(qIndex) {
+			if err := mp.crypto.VerifyProofCerts(types.StringToBytes(pOrder.PreOrderDigest()), pOrder.QC, mp.quorum); err != nil {
+				return nil, fmt.Errorf("invalid high partial order received from %d: %s", batch.Author, err)
+			}
+		}
+
+		updated = true
+	}
+
+	var qStream types.QueryStream
+
+	if updated {
+		for index, no := range batch.SeqList {
+			id := mp.members[index]
+
+			for {
+				if no <= mp.commitNo[id] {
+					break
+				}
+
+				mp.commitNo[id]++
+
+				qIndex := types.NewQueryIndex(id, mp.commitNo[id])
+				qStream = append(qStream, qIndex)
 			}
+		}
 
-			mp.commitNo[id]++
+		// persist the committed number for each participant.
+		commitNo := make([]uint64, len(mp.members))
+		for index, id := range mp.members {
+			commitNo[index] = mp.commitNo[id]
+		}
+		if err := mp.wal.Append(&protos.WALEntry{Type: protos.WALEntryType_WAL_COMMIT, CommitNo: commitNo}); err != nil {
+			return nil, fmt.Errorf("persist committed query stream failed: %s", err)
+		}
+	}
 
-			qIndex := types.NewQueryIndex(id, mp.commitNo[id])
-			qStream = append(qStream, qIndex)
+	// the membership change takes effect after the query stream of current batch.
+	if reconf != nil {
+		if err := mp.wal.Append(&protos.WALEntry{Type: protos.WALEntryType_WAL_RECONFIGURATION, Reconfiguration: reconf}); err != nil {
+			return nil, fmt.Errorf("persist reconfiguration failed: %s", err)
+		}
+		if err := mp.reconfigure(reconf); err != nil {
+			return nil, fmt.Errorf("reconfiguration failed: %s", err)
+		}
+		if err := mp.recheckPending(); err != nil {
+			return nil, fmt.Errorf("reconfiguration failed: %s", err)
 		}
 	}
 
 	return qStream, nil
 }
+
+//=====================================================================
+//                     Epoch Manager
+//=====================================================================
+
+// ProposeReconfiguration records the membership change which would be proposed with the next partial order batch.
+func (mp *metaPool) ProposeReconfiguration(reconf *protos.Reconfiguration) error {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	if reconf.Epoch != mp.epoch+1 {
+		return fmt.Errorf("invalid reconfiguration epoch, expect %d, received %d", mp.epoch+1, reconf.Epoch)
+	}
+
+	if err := mp.verifyReconfiguration(reconf); err != nil {
+		return fmt.Errorf("invalid reconfiguration: %s", err)
+	}
+
+	mp.logger.Infof("[%d] propose reconfiguration %s", mp.author, reconf.Format())
+	mp.pending = reconf
+	return nil
+}
+
+// SignReconfiguration generates the signature of current node for the membership change, and the reconfiguration
+// should carry the quorum-cert aggregated with the signatures of participants in current epoch.
+func (mp *metaPool) SignReconfiguration(reconf *protos.Reconfiguration) (*protos.Certification, error) {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+
+	if reconf.Epoch != mp.epoch+1 {
+		return nil, fmt.Errorf("invalid reconfiguration epoch, expect %d, received %d", mp.epoch+1, reconf.Epoch)
+	}
+
+	if _, err := mp.decodeReconfiguration(reconf); err != nil {
+		return nil, fmt.Errorf("invalid reconfiguration: %s", err)
+	}
+
+	digest, err := types.CalculateReconfigurationDigest(mp.hasher, reconf)
+	if err != nil {
+		return nil, fmt.Errorf("reconfiguration marshal error: %s", err)
+	}
+	return mp.crypto.PrivateSign(types.StringToBytes(digest))
+}
+
+// Members returns the current epoch number and the sorted identifiers of participants.
+func (mp *metaPool) Members() (uint64, []uint64) {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+
+	members := make([]uint64, len(mp.members))
+	copy(members, mp.members)
+	return mp.epoch, members
+}
+
+// verifyReconfiguration is used to check the membership change has been authorized by a quorum of participants
+// in current epoch, and the new public keys have been registered with proof-of-possession.
+func (mp *metaPool) verifyReconfiguration(reconf *protos.Reconfiguration) error {
+	digest, err := types.CalculateReconfigurationDigest(mp.hasher, reconf)
+	if err != nil {
+		return fmt.Errorf("reconfiguration marshal error: %s", err)
+	}
+	if err := mp.crypto.VerifyProofCerts(types.StringToBytes(digest), reconf.QC, mp.quorum); err != nil {
+		return fmt.Errorf("invalid reconfiguration quorum-cert: %s", err)
+	}
+
+	_, err = mp.decodeReconfiguration(reconf)
+	return err
+}
+
+// decodeReconfiguration is used to check the membership and decode the public keys in reconfiguration.
+func (mp *metaPool) decodeReconfiguration(reconf *protos.Reconfiguration) (map[uint64]external.PublicKey, error) {
+	if len(reconf.Replicas) == 0 {
+		return nil, fmt.Errorf("empty membership")
+	}
+
+	keys := make(map[uint64]external.PublicKey)
+	seen := make(map[uint64]bool)
+	for _, replica := range reconf.Replicas {
+		if replica.ID == 0 || seen[replica.ID] {
+			return nil, fmt.Errorf("invalid or duplicated replica %d", replica.ID)
+		}
+		seen[replica.ID] = true
+
+		if replica.PublicKey == nil {
+			// keep the public key in previous epoch.
+			continue
+		}
+
+		if mp.decoder == nil {
+			return nil, fmt.Errorf("cannot decode public key for node %d without decoder", replica.ID)
+		}
+		key, err := mp.decoder.DecodePublicKey(replica.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("decode public key for node %d failed: %s", replica.ID, err)
+		}
+
+		// the signatures would be aggregated, and a rogue public key could forge the aggregated ones of others.
+		if mp.crypto.Aggregated() {
+			verifier, ok := key.(external.PossessionVerifier)
+			if !ok {
+				return nil, fmt.Errorf("cannot verify proof-of-possession for node %d with algorithm %s", replica.ID, key.Algorithm())
+			}
+			if err := verifier.VerifyPossession(replica.Possession); err != nil {
+				return nil, fmt.Errorf("invalid proof-of-possession for node %d: %s", replica.ID, err)
+			}
+		}
+		keys[replica.ID] = key
+	}
+	return keys, nil
+}
+
+// reconfigure is used to change the membership of current cluster at the boundary of committed batch.
+// the replica instances and committed numbers of the remaining participants would be kept.
+func (mp *metaPool) reconfigure(reconf *protos.Reconfiguration) error {
+	keys, err := mp.decodeReconfiguration(reconf)
+	if err != nil {
+		return err
+	}
+
+	members := reconf.Members()
+	if err := mp.crypto.UpdateVerifiers(members, keys); err != nil {
+		return err
+	}
+
+	quorum := types.CalculateQuorum(len(members))
+	replicas := make(map[uint64]api.ReplicaInstance, len(members))
+	commitNo := make(map[uint64]uint64, len(members))
+	for _, id := range members {
+		replica, ok := mp.replicas[id]
+		if !ok {
+			replica = mp.builder(id, quorum)
+		}
+		replica.UpdateQuorum(quorum)
+		replicas[id] = replica
+		commitNo[id] = mp.commitNo[id]
+	}
+
+	// the client instances are initiated for the clients of the new participants.
+	for i := 0; i < len(members)*mp.multi; i++ {
+		id := uint64(i + 1)
+		if _, ok := mp.clients[id]; !ok {
+			mp.clients[id] = instance.NewClient(mp.author, id, mp.commandC, mp.ctx.Done(), mp.overload, mp.active, mp.logger)
+		}
+	}
+
+	// the broadcast messages should be sent to the participants of the new membership.
+	if network, ok := mp.sender.(external.MembershipAware); ok {
+		network.UpdateMembers(members)
+	}
+
+	mp.epoch = reconf.Epoch
+	mp.history = append(mp.history, reconf)
+	mp.members = members
+	mp.n = len(members)
+	mp.quorum = quorum
+	mp.replicas = replicas
+	mp.commitNo = commitNo
+	if mp.pending != nil && mp.pending.Epoch <= mp.epoch {
+		mp.pending = nil
+	}
+
+	mp.logger.Infof("[%d] reconfigured to epoch %d, members %v, quorum %d", mp.author, mp.epoch, mp.members, mp.quorum)
+	return nil
+}
+
+// recheckPending discards the votes from the removed members for our pending pre-orders, and generates the partial
+// orders for the ones which have collected quorum votes with the threshold of new membership.
+func (mp *metaPool) recheckPending() error {
+	isMember := make(map[uint64]bool, len(mp.members))
+	for _, id := range mp.members {
+		isMember[id] = true
+	}
+
+	var pending []*protos.PartialOrder
+	for _, pOrder := range mp.aggMap {
+		certs := pOrder.QC.Certs[:0]
+		for _, entry := range pOrder.QC.Certs {
+			if isMember[entry.ID] {
+				certs = append(certs, entry)
+			}
+		}
+		pOrder.QC.Certs = certs
+		pending = append(pending, pOrder)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Sequence() < pending[j].Sequence() })
+
+	for _, pOrder := range pending {
+		if len(pOrder.QC.Certs) < mp.quorum {
+			continue
+		}
+		if err := mp.quorumOrder(pOrder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//=====================================================================
+//                     Misbehavior Detection
+//=====================================================================
+
+// ProcessMisbehavior is used to process the proof of misbehavior detected by others.
+func (mp *metaPool) ProcessMisbehavior(proof *protos.MisbehaviorProof) error {
+	mp.mutex.RLock()
+	quorum := mp.quorum
+	mp.mutex.RUnlock()
+
+	if err := mp.crypto.VerifyMisbehavior(proof, quorum); err != nil {
+		return fmt.Errorf("invalid misbehavior proof: %s", err)
+	}
+
+	if proof.IsClientMisbehavior() {
+		// the commands are signed by the client, which is out of the view of crypto module.
+		for _, command := range []*protos.Command{proof.FirstCommand, proof.SecondCommand} {
+			if err := mp.verifyCommand(command); err != nil {
+				return fmt.Errorf("invalid misbehavior proof: %s", err)
+			}
+		}
+	}
+
+	mp.reporter.ReportMisbehavior(proof)
+	return nil
+}
+
+// reportEquivocation is used to report the client which has issued the command conflicting with a recorded one.
+// Both of them are kept in command tracker, and we would only propose the first one with our pre-orders.
+func (mp *metaPool) reportEquivocation(command *protos.Command) {
+	first := mp.cTracker.GetCommandByIndex(types.QueryIndex{Author: command.Author, SeqNo: command.Sequence})
+	if first == nil {
+		return
+	}
+
+	proof := &protos.MisbehaviorProof{
+		Type:          protos.MisbehaviorType_CLIENT_EQUIVOCATION,
+		Offender:      command.Author,
+		Sequence:      command.Sequence,
+		FirstCommand:  first,
+		SecondCommand: command,
+	}
+	mp.reporter.ReportMisbehavior(proof)
+}
+
+//=====================================================================
+//                     Checkpoint Manager
+//=====================================================================
+
+// Checkpoint notifies meta pool the stable checkpoint, so that the states below it could be garbage collected.
+func (mp *metaPool) Checkpoint(checkpoint types.Checkpoint) {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	mp.logger.Infof("[%d] received checkpoint %d, watermarks %v", mp.author, checkpoint.Sequence, checkpoint.Watermarks)
+
+	// garbage collect the states below previous checkpoint.
+	if mp.stable.Watermarks != nil {
+		mp.pTracker.Checkpoint(mp.stable.Watermarks)
+		mp.reporter.Checkpoint(mp.stable.Watermarks)
+	}
+	mp.cTracker.Checkpoint()
+
+	mp.stable = checkpoint
+
+	// compact the write-ahead log with current status, so that it wouldn't grow without bound.
+	if err := mp.compact(); err != nil {
+		mp.logger.Errorf("[%d] compact write-ahead log failed: %s", mp.author, err)
+	}
+}
+
+// compact replaces the entries in write-ahead log with a snapshot of current status.
+func (mp *metaPool) compact() error {
+	// the replica instances may persist votes and partial orders concurrently, and the ones appended after offset
+	// would be kept behind the snapshot.
+	offset := mp.wal.Offset()
+
+	var entries []*protos.WALEntry
+
+	// the membership changes are kept to recover the public keys of each epoch.
+	for _, reconf := range mp.history {
+		entries = append(entries, &protos.WALEntry{Type: protos.WALEntryType_WAL_RECONFIGURATION, Reconfiguration: reconf})
+	}
+
+	snapshot := &protos.WALSnapshot{HighOrder: mp.highOrder, CommitNo: make([]uint64, len(mp.members))}
+	for index, id := range mp.members {
+		snapshot.CommitNo[index] = mp.commitNo[id]
+	}
+	for _, pOrder := range mp.aggMap {
+		snapshot.Pending = append(snapshot.Pending, pOrder.PreOrder)
+	}
+	sort.Slice(snapshot.Pending, func(i, j int) bool { return snapshot.Pending[i].Sequence < snapshot.Pending[j].Sequence })
+	entries = append(entries, &protos.WALEntry{Type: protos.WALEntryType_WAL_SNAPSHOT, Snapshot: snapshot})
+
+	for _, id := range mp.members {
+		entries = append(entries, mp.replicas[id].Snapshot(mp.commitNo[id])...)
+	}
+
+	return mp.wal.Compact(offset, entries)
+}
+
+//=====================================================================
+//                     Write-Ahead Log Recovery
+//=====================================================================
+
+// replay is used to recover the status of meta pool with the entries persisted in write-ahead log.
+func (mp *metaPool) replay() error {
+	return mp.wal.Replay(func(entry *protos.WALEntry) error {
+		switch entry.Type {
+		case protos.WALEntryType_WAL_PRE_ORDER:
+			return mp.recoverPreOrder(entry.PreOrder)
+
+		case protos.WALEntryType_WAL_VOTE:
+			replica, ok := mp.replicas[entry.PreOrder.Author]
+			if !ok {
+				return fmt.Errorf("cannot find replica instance for node %d", entry.PreOrder.Author)
+			}
+			return replica.Recover(entry)
+
+		case protos.WALEntryType_WAL_PARTIAL:
+			if entry.Partial.Author() == mp.author {
+				// the pre-order of ourselves has collected quorum votes.
+				delete(mp.aggMap, entry.Partial.PreOrderDigest())
+			}
+			replica, ok := mp.replicas[entry.Partial.Author()]
+			if !ok {
+				return fmt.Errorf("cannot find replica instance for node %d", entry.Partial.Author())
+			}
+			return replica.Recover(entry)
+
+		case protos.WALEntryType_WAL_COMMIT:
+			if len(entry.CommitNo) != len(mp.members) {
+				return fmt.Errorf("invalid committed number size, expect %d, received %d", len(mp.members), len(entry.CommitNo))
+			}
+			for index, no := range entry.CommitNo {
+				mp.commitNo[mp.members[index]] = no
+			}
+			return nil
+
+		case protos.WALEntryType_WAL_RECONFIGURATION:
+			return mp.reconfigure(entry.Reconfiguration)
+
+		case protos.WALEntryType_WAL_SNAPSHOT:
+			return mp.recoverSnapshot(entry.Snapshot)
+
+		case protos.WALEntryType_WAL_BASE_PARTIAL:
+			replica, ok := mp.replicas[entry.Partial.Author()]
+			if !ok {
+				return fmt.Errorf("cannot find replica instance for node %d", entry.Partial.Author())
+			}
+			return replica.Recover(entry)
+
+		default:
+			return fmt.Errorf("invalid entry type %s", entry.Type)
+		}
+	})
+}
+
+// recoverPreOrder is used to restore the highest pre-order we have generated.
+func (mp *metaPool) recoverPreOrder(pre *protos.PreOrder) error {
+	if err := mp.checkHighOrder(); err != nil {
+		return fmt.Errorf("highest partial order error: %s", err)
+	}
+
+	if pre.Sequence != mp.sequence+1 {
+		return fmt.Errorf("invalid pre-order sequence, expect %d, received %d", mp.sequence+1, pre.Sequence)
+	}
+
+	signature, err := mp.crypto.PrivateSign(types.StringToBytes(pre.Digest))
+	if err != nil {
+		return fmt.Errorf("generate signature for pre-order failed: %s", err)
+	}
+
+	if pre.Signature == nil {
+		pre.Signature = signature
+	}
+
+	mp.sequence = pre.Sequence
+	mp.aggMap[pre.Digest] = protos.NewPartialOrder(pre)
+	mp.aggMap[pre.Digest].QC.AddCert(mp.author, signature)
+	mp.updateHighOrder(pre)
+
+	mp.logger.Infof("[%d] recover pre-order %s", mp.author, pre.Format())
+	return nil
+}
+
+// recoverSnapshot is used to restore the status of meta pool we have compacted the write-ahead log with.
+func (mp *metaPool) recoverSnapshot(snapshot *protos.WALSnapshot) error {
+	if len(snapshot.CommitNo) != len(mp.members) {
+		return fmt.Errorf("invalid committed number size, expect %d, received %d", len(mp.members), len(snapshot.CommitNo))
+	}
+	for index, no := range snapshot.CommitNo {
+		mp.commitNo[mp.members[index]] = no
+	}
+
+	if snapshot.HighOrder != nil {
+		mp.sequence = snapshot.HighOrder.Sequence
+		mp.updateHighOrder(snapshot.HighOrder)
+	}
+
+	mp.aggMap = make(map[string]*protos.PartialOrder)
+	for _, pre := range snapshot.Pending {
+		if pre.Signature == nil || pre.Sequence > mp.sequence {
+			return fmt.Errorf("invalid pending pre-order %s", pre.Format())
+		}
+		mp.aggMap[pre.Digest] = protos.NewPartialOrder(pre)
+		mp.aggMap[pre.Digest].QC.AddCert(mp.author, pre.Signature)
+	}
+
+	mp.logger.Infof("[%d] recover snapshot, sequence %d, pending pre-orders %d", mp.author, mp.sequence, len(mp.aggMap))
+	return nil
+}
+
+// rebroadcastPreOrders is used to send the pre-orders which are still waiting for quorum votes.
+func (mp *metaPool) rebroadcastPreOrders() {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	var pending []*protos.PreOrder
+	for _, pOrder := range mp.aggMap {
+		pending = append(pending, pOrder.PreOrder)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Sequence < pending[j].Sequence })
+
+	for _, pre := range pending {
+		mp.logger.Infof("[%d] re-broadcast recovered pre-order %s", mp.author, pre.Format())
+
+		cm, err := protos.PackPreOrder(pre)
+		if err != nil {
+			mp.logger.Errorf("[%d] generate consensus message error: %s", mp.author, err)
+			continue
+		}
+		mp.sender.BroadcastPCM(cm)
+		mp.startRetransmitTimer(pre.Digest)
+	}
+}
@@ -0,0 +1,97 @@
+package metapool
+
+import (
+	"sync"
+
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
+	"github.com/Grivn/phalanx/external"
+)
+
+// misbehaviorReporter is used to collect the proofs of misbehavior detected by replica instances or received from
+// others, and each new proof would be broadcast to others and notified to the embedding chain.
+type misbehaviorReporter struct {
+	// mutex is used to control the concurrency problems of reporter.
+	mutex sync.Mutex
+
+	// author indicates current node identifier.
+	author uint64
+
+	// reported records the offender and sequence number which we have reported,
+	// so that the same misbehavior would only be reported once.
+	reported map[types.QueryIndex]bool
+
+	// reportedClients records the clients and sequence numbers which we have reported,
+	// since the identifiers of clients are independent of the ones of participants.
+	reportedClients map[types.QueryIndex]bool
+
+	// stableClients records the clients and sequence numbers reported before the latest checkpoint. There isn't any
+	// watermark for clients in checkpoint, so that they are kept for one more checkpoint as the committed commands.
+	stableClients map[types.QueryIndex]bool
+
+	// handler is used to process the misbehavior, and a nil one means we only keep the evidence.
+	handler external.MisbehaviorService
+
+	// sender is used to broadcast the proofs to others.
+	sender external.NetworkService
+
+	// logger prints logs.
+	logger external.Logger
+}
+
+func newMisbehaviorReporter(author uint64, handler external.MisbehaviorService, sender external.NetworkService, logger external.Logger) *misbehaviorReporter {
+	return &misbehaviorReporter{
+		author:          author,
+		reported:        make(map[types.QueryIndex]bool),
+		reportedClients: make(map[types.QueryIndex]bool),
+		stableClients:   make(map[types.QueryIndex]bool),
+		handler:         handler,
+		sender:          sender,
+		logger:          logger,
+	}
+}
+
+func (r *misbehaviorReporter) ReportMisbehavior(proof *protos.MisbehaviorProof) {
+	idx := types.QueryIndex{Author: proof.Offender, SeqNo: proof.Sequence}
+
+	r.mutex.Lock()
+	reported := r.reported
+	if proof.IsClientMisbehavior() {
+		reported = r.reportedClients
+	}
+
+	if reported[idx] || (proof.IsClientMisbehavior() && r.stableClients[idx]) {
+		r.mutex.Unlock()
+		return
+	}
+	reported[idx] = true
+	r.mutex.Unlock()
+
+	r.logger.Errorf("[%d] found misbehavior %s", r.author, proof.Format())
+
+	cm, err := protos.PackMisbehavior(proof, r.author)
+	if err != nil {
+		r.logger.Errorf("[%d] generate consensus message error: %s", r.author, err)
+	} else {
+		r.sender.BroadcastPCM(cm)
+	}
+
+	if r.handler != nil {
+		r.handler.ReportMisbehavior(proof)
+	}
+}
+
+// Checkpoint garbage collects the records of misbehavior below the watermarks of participants, and the ones of clients
+// reported before previous checkpoint.
+func (r *misbehaviorReporter) Checkpoint(watermarks map[uint64]uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for idx := range r.reported {
+		if idx.SeqNo <= watermarks[idx.Author] {
+			delete(r.reported, idx)
+		}
+	}
+	r.stableClients = r.reportedClients
+	r.reportedClients = make(map[types.QueryIndex]bool)
+}
@@ -1,10 +1,13 @@
 package tracker
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/Grivn/phalanx/common/api"
 	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
 	"github.com/Grivn/phalanx/external"
 )
 
@@ -22,55 +25,151 @@ type commandTracker struct {
 	// commandMap records the commands current node has received.
 	commandMap map[string]*protos.Command
 
-	//
+	// commandCnt records the number of ordering strategies which have read the command.
 	commandCnt map[string]int
 
-	//
+	// threshold is the number of ordering strategies, and the command would be treated as a committed one
+	// once all of them have read it.
 	threshold int
 
-	// committedMap records the commands which have been committed.
-	committedMap map[string]bool
+	// committedMap records the commands which have been committed since the latest checkpoint.
+	// we would like to keep them to serve the fetch-missing requests from others.
+	committedMap map[string]*protos.Command
+
+	// stableMap records the commands which have been committed between the latest two checkpoints,
+	// and the ones committed before them have been garbage collected.
+	stableMap map[string]*protos.Command
+
+	// receivedTime records the time when we first received the commands which haven't been committed.
+	receivedTime map[string]int64
+
+	// indexMap records the digests of commands we have recorded with the identifier of client and sequence number,
+	// so that we could serve the fetch requests for the missing commands which stall the clients of others.
+	indexMap map[types.QueryIndex]string
+
+	// limit is the size limit of commands we would record.
+	limit types.CommandLimit
+
+	// hasher is used to check the digests of commands.
+	hasher types.Hasher
+
+	// waiters records the notification channels for the readers who are waiting for specific commands.
+	waiters map[string]chan struct{}
 
 	// logger prints logs.
 	logger external.Logger
 }
 
-func NewCommandTracker(author uint64, logger external.Logger) api.CommandTracker {
+func NewCommandTracker(author uint64, readers int, limit types.CommandLimit, hasher types.Hasher, logger external.Logger) api.CommandTracker {
 	logger.Infof("[%d] initiate command tracker", author)
+	if readers <= 0 {
+		readers = 1
+	}
 	return &commandTracker{
 		author:       author,
 		commandMap:   make(map[string]*protos.Command),
 		commandCnt:   make(map[string]int),
-		committedMap: make(map[string]bool),
-		threshold:    3,
+		committedMap: make(map[string]*protos.Command),
+		stableMap:    make(map[string]*protos.Command),
+		receivedTime: make(map[string]int64),
+		indexMap:     make(map[types.QueryIndex]string),
+		limit:        limit,
+		hasher:       hasher,
+		waiters:      make(map[string]chan struct{}),
+		threshold:    readers,
 		logger:       logger,
 	}
 }
 
-func (ct *commandTracker) RecordCommand(command *protos.Command) {
+func (ct *commandTracker) RecordCommand(command *protos.Command) error {
+	if ct.isRecorded(command.Digest) {
+		// duplicated or committed command, which has been validated.
+		return nil
+	}
+
+	// the commands are referred to with digest, so that we should make sure the content matches it,
+	// or the nodes may execute different payloads with the same digest.
+	if err := types.CheckCommand(ct.hasher, command, ct.limit); err != nil {
+		return err
+	}
+
 	ct.mutex.Lock()
 	defer ct.mutex.Unlock()
 
 	if _, ok := ct.commandMap[command.Digest]; ok {
 		// duplicated command.
 		ct.logger.Debugf("[%d] duplicated command %s", ct.author, command.Digest)
-		return
+		return nil
 	}
 
-	if ct.committedMap[command.Digest] {
+	if ct.isCommitted(command.Digest) {
 		// committed command
 		ct.logger.Debugf("[%d] committed command %s", ct.author, command.Digest)
-		return
+		return nil
 	}
 
 	//ct.logger.Debugf("[%d] received command %s", ct.author, command.Digest)
 	ct.commandMap[command.Digest] = command
+	ct.receivedTime[command.Digest] = time.Now().UnixNano()
+
+	// notify the readers waiting for current command.
+	if waitC, ok := ct.waiters[command.Digest]; ok {
+		close(waitC)
+		delete(ct.waiters, command.Digest)
+	}
+
+	// the conflicting command is kept as evidence, and it may also be referred by the partial orders of others,
+	// but the index always refers to the first one we have received.
+	idx := types.QueryIndex{Author: command.Author, SeqNo: command.Sequence}
+	if digest, ok := ct.indexMap[idx]; ok && digest != command.Digest {
+		ct.logger.Errorf("[%d] client %d equivocates on sequence %d, digest %s and %s", ct.author, command.Author, command.Sequence, digest, command.Digest)
+		return types.ErrClientEquivocation
+	}
+	ct.indexMap[idx] = command.Digest
+	return nil
 }
 
 func (ct *commandTracker) ReadCommand(digest string) *protos.Command {
-	ct.mutex.RLock()
-	defer ct.mutex.RUnlock()
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
 
+	return ct.readCommand(digest)
+}
+
+func (ct *commandTracker) WaitCommand(ctx context.Context, digest string) *protos.Command {
+	return ct.waitCommand(ctx, digest, ct.readCommand)
+}
+
+func (ct *commandTracker) PeekCommand(ctx context.Context, digest string) *protos.Command {
+	return ct.waitCommand(ctx, digest, ct.getCommand)
+}
+
+// waitCommand blocks until the command could be read with the read function, which is called with the lock held.
+func (ct *commandTracker) waitCommand(ctx context.Context, digest string, read func(digest string) *protos.Command) *protos.Command {
+	for {
+		ct.mutex.Lock()
+		if command := read(digest); command != nil {
+			ct.mutex.Unlock()
+			return command
+		}
+
+		// register a waiter for current digest, and it would be notified once the command has been recorded.
+		waitC, ok := ct.waiters[digest]
+		if !ok {
+			waitC = make(chan struct{})
+			ct.waiters[digest] = waitC
+		}
+		ct.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-waitC:
+		}
+	}
+}
+
+func (ct *commandTracker) readCommand(digest string) *protos.Command {
 	command, ok := ct.commandMap[digest]
 	if !ok {
 		return nil
@@ -79,9 +178,81 @@ func (ct *commandTracker) ReadCommand(digest string) *protos.Command {
 	ct.commandCnt[digest]++
 	if ct.commandCnt[digest] == ct.threshold {
 		delete(ct.commandMap, digest)
-		ct.committedMap[digest] = true
+		delete(ct.commandCnt, digest)
+		delete(ct.receivedTime, digest)
+		ct.committedMap[digest] = command
 	}
 
 	ct.logger.Debugf("[%d] read command %s", ct.author, digest)
 	return command
 }
+
+func (ct *commandTracker) ReceivedTime(digest string) (int64, bool) {
+	ct.mutex.RLock()
+	defer ct.mutex.RUnlock()
+
+	rTime, ok := ct.receivedTime[digest]
+	return rTime, ok
+}
+
+func (ct *commandTracker) GetCommand(digest string) *protos.Command {
+	ct.mutex.RLock()
+	defer ct.mutex.RUnlock()
+
+	return ct.getCommand(digest)
+}
+
+func (ct *commandTracker) getCommand(digest string) *protos.Command {
+	if command, ok := ct.commandMap[digest]; ok {
+		return command
+	}
+	if command, ok := ct.committedMap[digest]; ok {
+		return command
+	}
+	return ct.stableMap[digest]
+}
+
+func (ct *commandTracker) GetCommandByIndex(idx types.QueryIndex) *protos.Command {
+	ct.mutex.RLock()
+	digest, ok := ct.indexMap[idx]
+	ct.mutex.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	return ct.GetCommand(digest)
+}
+
+func (ct *commandTracker) Checkpoint() {
+	ct.mutex.Lock()
+	defer ct.mutex.Unlock()
+
+	ct.logger.Infof("[%d] garbage collect %d committed commands", ct.author, len(ct.stableMap))
+	for _, command := range ct.stableMap {
+		idx := types.QueryIndex{Author: command.Author, SeqNo: command.Sequence}
+		if ct.indexMap[idx] == command.Digest {
+			delete(ct.indexMap, idx)
+		}
+	}
+	ct.stableMap = ct.committedMap
+	ct.committedMap = make(map[string]*protos.Command)
+}
+
+func (ct *commandTracker) isCommitted(digest string) bool {
+	if _, ok := ct.committedMap[digest]; ok {
+		return true
+	}
+	_, ok := ct.stableMap[digest]
+	return ok
+}
+
+// isRecorded checks if the command has been recorded or committed.
+func (ct *commandTracker) isRecorded(digest string) bool {
+	ct.mutex.RLock()
+	defer ct.mutex.RUnlock()
+
+	if _, ok := ct.commandMap[digest]; ok {
+		return true
+	}
+	return ct.isCommitted(digest)
+}
@@ -1,6 +1,7 @@
 package tracker
 
 import (
+	"context"
 	"sync"
 
 	"github.com/Grivn/phalanx/common/api"
@@ -20,6 +21,19 @@ type partialTracker struct {
 	// partialMap records the partial orders which current node has received.
 	partialMap sync.Map
 
+	// committedMap records the partial orders which have been read by executor,
+	// so that we could still return them to the nodes in fetch-missing process.
+	committedMap sync.Map
+
+	// mutex is used to control the concurrency problems of waiters and watermarks.
+	mutex sync.Mutex
+
+	// watermarks are the sequence numbers for each participant below which the partial orders have been garbage collected.
+	watermarks map[uint64]uint64
+
+	// waiters records the notification channels for the readers who are waiting for specific partial orders.
+	waiters map[types.QueryIndex]chan struct{}
+
 	// logger prints logs.
 	logger external.Logger
 }
@@ -27,20 +41,38 @@ type partialTracker struct {
 func NewPartialTracker(author uint64, logger external.Logger) api.PartialTracker {
 	logger.Infof("[%d] initiate partial tracker")
 	return &partialTracker{
-		author: author,
-		logger: logger,
+		author:     author,
+		waiters:    make(map[types.QueryIndex]chan struct{}),
+		watermarks: make(map[uint64]uint64),
+		logger:     logger,
 	}
 }
 
 func (pt *partialTracker) RecordPartial(pOrder *protos.PartialOrder) {
 	qIdx := types.QueryIndex{Author: pOrder.Author(), SeqNo: pOrder.Sequence()}
 
+	pt.mutex.Lock()
+	watermark := pt.watermarks[qIdx.Author]
+	pt.mutex.Unlock()
+	if qIdx.SeqNo <= watermark {
+		pt.logger.Debugf("[%d] partial order %s is below watermark %d", pt.author, pOrder.Format(), watermark)
+		return
+	}
+
 	if _, ok := pt.partialMap.Load(qIdx); ok {
 		pt.logger.Debugf("[%d] duplicated partial order %s", pt.author, pOrder.Format())
 		return
 	}
 
 	pt.partialMap.Store(qIdx, pOrder)
+
+	// notify the readers waiting for current partial order.
+	pt.mutex.Lock()
+	if waitC, ok := pt.waiters[qIdx]; ok {
+		close(waitC)
+		delete(pt.waiters, qIdx)
+	}
+	pt.mutex.Unlock()
 }
 
 func (pt *partialTracker) ReadPartial(idx types.QueryIndex) *protos.PartialOrder {
@@ -51,10 +83,76 @@ func (pt *partialTracker) ReadPartial(idx types.QueryIndex) *protos.PartialOrder
 	}
 	pOrder := e.(*protos.PartialOrder)
 	pt.partialMap.Delete(idx)
+	pt.committedMap.Store(idx, pOrder)
 	return pOrder
 }
 
+func (pt *partialTracker) WaitPartial(ctx context.Context, idx types.QueryIndex) *protos.PartialOrder {
+	for {
+		if pOrder := pt.ReadPartial(idx); pOrder != nil {
+			return pOrder
+		}
+
+		// register a waiter for current query index, and it would be notified once the partial order has been recorded.
+		pt.mutex.Lock()
+		waitC, ok := pt.waiters[idx]
+		if !ok {
+			waitC = make(chan struct{})
+			pt.waiters[idx] = waitC
+		}
+		pt.mutex.Unlock()
+
+		// check again in case the partial order was recorded before the waiter registration.
+		if pOrder := pt.ReadPartial(idx); pOrder != nil {
+			return pOrder
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-waitC:
+		}
+	}
+}
+
+func (pt *partialTracker) GetPartial(idx types.QueryIndex) *protos.PartialOrder {
+	// here, we are trying to find the partial order for fetch-missing process,
+	// which may have been read by executor.
+	if e, ok := pt.partialMap.Load(idx); ok {
+		return e.(*protos.PartialOrder)
+	}
+	if e, ok := pt.committedMap.Load(idx); ok {
+		return e.(*protos.PartialOrder)
+	}
+	return nil
+}
+
 func (pt *partialTracker) IsExist(idx types.QueryIndex) bool {
 	_, ok := pt.partialMap.Load(idx)
 	return ok
 }
+
+func (pt *partialTracker) Checkpoint(watermarks map[uint64]uint64) {
+	pt.mutex.Lock()
+	for id, watermark := range watermarks {
+		pt.watermarks[id] = watermark
+	}
+	pt.mutex.Unlock()
+
+	// the partial orders below watermarks have been read by executor, remove them.
+	count := 0
+	prune := func(key, value interface{}) bool {
+		idx := key.(types.QueryIndex)
+		if idx.SeqNo <= watermarks[idx.Author] {
+			pt.committedMap.Delete(idx)
+			pt.partialMap.Delete(idx)
+			count++
+		}
+		return true
+	}
+	pt.committedMap.Range(prune)
+
+	// the ones recorded again after being read, or recovered below committed numbers, would never be read.
+	pt.partialMap.Range(prune)
+	pt.logger.Infof("[%d] garbage collect %d partial orders", pt.author, count)
+}
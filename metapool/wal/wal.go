@@ -0,0 +1,274 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Grivn/phalanx/common/api"
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/external"
+	"github.com/gogo/protobuf/proto"
+)
+
+// headerSize is the size of record header: 4 bytes for payload length and 4 bytes for crc32 checksum.
+const headerSize = 8
+
+// maxRecordSize is the upper bound of payload size, a larger one could only be produced by a corrupted header.
+const maxRecordSize = 64 << 20
+
+// writeAheadLog is an append-only file which is used to persist the essential messages of meta pool.
+// each record is encoded as: length(4 bytes) | crc32(4 bytes) | payload, and each append would be
+// synchronized into disk before return, so that the messages we have sent out could always be recovered.
+type writeAheadLog struct {
+	// mutex is used to control the concurrency problems of write-ahead log.
+	mutex sync.Mutex
+
+	// author indicates current node identifier.
+	author uint64
+
+	// path is the location of write-ahead log, which is used to replace the file in compaction.
+	path string
+
+	// file is the append-only file for write-ahead log.
+	file *os.File
+
+	// offset is the size of valid records we have persisted.
+	offset int64
+
+	// logger prints logs.
+	logger external.Logger
+}
+
+// NewWriteAheadLog opens the write-ahead log with given path, and a blank path means we don't need a durable log.
+func NewWriteAheadLog(author uint64, path string, logger external.Logger) (api.WriteAheadLog, error) {
+	if path == "" {
+		logger.Infof("[%d] write-ahead log is disabled", author)
+		return &nopWriteAheadLog{}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create directory for write-ahead log failed: %s", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open write-ahead log failed: %s", err)
+	}
+
+	logger.Infof("[%d] initiate write-ahead log %s", author, path)
+	return &writeAheadLog{author: author, path: path, file: file, logger: logger}, nil
+}
+
+func (w *writeAheadLog) Append(entry *protos.WALEntry) error {
+	record, err := encodeRecord(entry)
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, err := w.file.Write(record); err != nil {
+		return fmt.Errorf("write write-ahead log failed: %s", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("sync write-ahead log failed: %s", err)
+	}
+	w.offset += int64(len(record))
+	return nil
+}
+
+func (w *writeAheadLog) Offset() int64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.offset
+}
+
+func (w *writeAheadLog) Compact(offset int64, entries []*protos.WALEntry) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if offset > w.offset {
+		return fmt.Errorf("invalid compaction offset %d, log size %d", offset, w.offset)
+	}
+
+	// write the snapshot and the records appended after offset into a temporary file, and then replace the log
+	// with it, so that a crash during compaction would never lose the persisted records.
+	tmpPath := w.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("open compacted write-ahead log failed: %s", err)
+	}
+
+	size, err := w.writeCompacted(tmp, offset, entries)
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, w.path); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("replace write-ahead log failed: %s", err)
+	}
+	if dir, err := os.Open(filepath.Dir(w.path)); err == nil {
+		_ = dir.Sync()
+		_ = dir.Close()
+	}
+
+	if err := w.file.Close(); err != nil {
+		w.logger.Errorf("[%d] close compacted write-ahead log failed: %s", w.author, err)
+	}
+	w.file = tmp
+	w.logger.Infof("[%d] compacted write-ahead log with %d entries, size %d -> %d", w.author, len(entries), w.offset, size)
+	w.offset = size
+	return nil
+}
+
+// writeCompacted writes the entries and the records after offset into file, and returns the size of it.
+func (w *writeAheadLog) writeCompacted(file *os.File, offset int64, entries []*protos.WALEntry) (int64, error) {
+	writer := bufio.NewWriter(file)
+	size := int64(0)
+	for _, entry := range entries {
+		record, err := encodeRecord(entry)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := writer.Write(record); err != nil {
+			return 0, fmt.Errorf("write compacted write-ahead log failed: %s", err)
+		}
+		size += int64(len(record))
+	}
+
+	tail, err := io.Copy(writer, io.NewSectionReader(w.file, offset, w.offset-offset))
+	if err != nil {
+		return 0, fmt.Errorf("copy write-ahead log records failed: %s", err)
+	}
+	size += tail
+
+	if err := writer.Flush(); err != nil {
+		return 0, fmt.Errorf("write compacted write-ahead log failed: %s", err)
+	}
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("sync compacted write-ahead log failed: %s", err)
+	}
+	return size, nil
+}
+
+func (w *writeAheadLog) Replay(process func(entry *protos.WALEntry) error) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("seek write-ahead log failed: %s", err)
+	}
+
+	reader := bufio.NewReader(w.file)
+	header := make([]byte, headerSize)
+	offset := int64(0)
+	count := 0
+
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err != io.EOF {
+				// the last record was torn by a crash while writing header.
+				w.logger.Errorf("[%d] found truncated write-ahead log header at offset %d", w.author, offset)
+			}
+			break
+		}
+
+		size := binary.BigEndian.Uint32(header[0:4])
+		if size > maxRecordSize {
+			w.logger.Errorf("[%d] found corrupted write-ahead log header at offset %d", w.author, offset)
+			break
+		}
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			// the last record was torn by a crash while writing payload.
+			w.logger.Errorf("[%d] found truncated write-ahead log record at offset %d", w.author, offset)
+			break
+		}
+
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+			w.logger.Errorf("[%d] found corrupted write-ahead log record at offset %d", w.author, offset)
+			break
+		}
+
+		entry := &protos.WALEntry{}
+		if err := proto.Unmarshal(payload, entry); err != nil {
+			return fmt.Errorf("unmarshal write-ahead log entry at offset %d failed: %s", offset, err)
+		}
+
+		if err := process(entry); err != nil {
+			return fmt.Errorf("replay write-ahead log entry at offset %d failed: %s", offset, err)
+		}
+
+		offset += int64(headerSize + len(payload))
+		count++
+	}
+
+	// drop the broken tail, so that the following records could be appended after the valid ones.
+	if err := w.file.Truncate(offset); err != nil {
+		return fmt.Errorf("truncate write-ahead log failed: %s", err)
+	}
+	if _, err := w.file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek write-ahead log failed: %s", err)
+	}
+	w.offset = offset
+
+	w.logger.Infof("[%d] replayed %d write-ahead log entries", w.author, count)
+	return nil
+}
+
+func (w *writeAheadLog) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.file.Close()
+}
+
+// encodeRecord encodes the entry with the header of payload length and checksum.
+func encodeRecord(entry *protos.WALEntry) ([]byte, error) {
+	payload, err := proto.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshal write-ahead log entry failed: %s", err)
+	}
+
+	record := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(payload))
+	copy(record[headerSize:], payload)
+	return record, nil
+}
+
+// nopWriteAheadLog is used when the durable log is disabled.
+type nopWriteAheadLog struct{}
+
+func (w *nopWriteAheadLog) Append(entry *protos.WALEntry) error {
+	return nil
+}
+
+func (w *nopWriteAheadLog) Replay(process func(entry *protos.WALEntry) error) error {
+	return nil
+}
+
+func (w *nopWriteAheadLog) Offset() int64 {
+	return 0
+}
+
+func (w *nopWriteAheadLog) Compact(offset int64, entries []*protos.WALEntry) error {
+	return nil
+}
+
+func (w *nopWriteAheadLog) Close() error {
+	return nil
+}
@@ -0,0 +1,159 @@
+package wal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Grivn/phalanx/common/api"
+	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
+)
+
+func newTestLog(t *testing.T, path string) api.WriteAheadLog {
+	wLog, err := NewWriteAheadLog(1, path, types.NewRawLogger())
+	if err != nil {
+		t.Fatalf("open write-ahead log failed: %s", err)
+	}
+	return wLog
+}
+
+func commitEntry(no uint64) *protos.WALEntry {
+	return &protos.WALEntry{Type: protos.WALEntryType_WAL_COMMIT, CommitNo: []uint64{no}}
+}
+
+func appendEntries(t *testing.T, wLog api.WriteAheadLog, nos ...uint64) {
+	for _, no := range nos {
+		if err := wLog.Append(commitEntry(no)); err != nil {
+			t.Fatalf("append entry failed: %s", err)
+		}
+	}
+}
+
+func replayEntries(t *testing.T, wLog api.WriteAheadLog) []uint64 {
+	var nos []uint64
+	err := wLog.Replay(func(entry *protos.WALEntry) error {
+		nos = append(nos, entry.CommitNo[0])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("replay failed: %s", err)
+	}
+	return nos
+}
+
+func checkEntries(t *testing.T, expect, actual []uint64) {
+	t.Helper()
+	if len(expect) != len(actual) {
+		t.Fatalf("expect entries %v, replayed %v", expect, actual)
+	}
+	for index := range expect {
+		if expect[index] != actual[index] {
+			t.Fatalf("expect entries %v, replayed %v", expect, actual)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+
+	wLog := newTestLog(t, path)
+	checkEntries(t, nil, replayEntries(t, wLog))
+	appendEntries(t, wLog, 1, 2, 3)
+	_ = wLog.Close()
+
+	wLog = newTestLog(t, path)
+	checkEntries(t, []uint64{1, 2, 3}, replayEntries(t, wLog))
+	_ = wLog.Close()
+}
+
+func TestReplayTornTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+
+	wLog := newTestLog(t, path)
+	replayEntries(t, wLog)
+	appendEntries(t, wLog, 1, 2)
+	_ = wLog.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := info.Size()
+
+	// a crash while writing the third record leaves a torn header and a torn payload.
+	for _, torn := range [][]byte{{0, 0}, {0, 0, 0, 16, 1, 2, 3, 4, 5}} {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = file.Write(torn)
+		_ = file.Close()
+
+		wLog = newTestLog(t, path)
+		checkEntries(t, []uint64{1, 2}, replayEntries(t, wLog))
+		_ = wLog.Close()
+
+		if info, _ = os.Stat(path); info.Size() != valid {
+			t.Fatalf("expect truncated size %d, actual %d", valid, info.Size())
+		}
+	}
+
+	// the following records are appended after the valid ones.
+	wLog = newTestLog(t, path)
+	replayEntries(t, wLog)
+	appendEntries(t, wLog, 3)
+	_ = wLog.Close()
+
+	wLog = newTestLog(t, path)
+	checkEntries(t, []uint64{1, 2, 3}, replayEntries(t, wLog))
+	_ = wLog.Close()
+}
+
+func TestReplayCorruptedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+
+	wLog := newTestLog(t, path)
+	replayEntries(t, wLog)
+	appendEntries(t, wLog, 1, 2)
+	_ = wLog.Close()
+
+	// flip the last byte of the second record, so that the checksum mismatches.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content[len(content)-1] ^= 0xff
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wLog = newTestLog(t, path)
+	checkEntries(t, []uint64{1}, replayEntries(t, wLog))
+	_ = wLog.Close()
+}
+
+func TestCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+
+	wLog := newTestLog(t, path)
+	replayEntries(t, wLog)
+	appendEntries(t, wLog, 1, 2)
+	offset := wLog.Offset()
+	appendEntries(t, wLog, 3)
+
+	// the entries before offset are replaced, and the ones appended after it are kept.
+	if err := wLog.Compact(offset, []*protos.WALEntry{commitEntry(10)}); err != nil {
+		t.Fatalf("compact failed: %s", err)
+	}
+	appendEntries(t, wLog, 4)
+	_ = wLog.Close()
+
+	wLog = newTestLog(t, path)
+	checkEntries(t, []uint64{10, 3, 4}, replayEntries(t, wLog))
+	_ = wLog.Close()
+
+	if _, err := os.Stat(path + ".compact"); !os.IsNotExist(err) {
+		t.Fatalf("expect temporary file removed, error %v", err)
+	}
+}
@@ -10,6 +10,8 @@ type Metrics struct {
 	PhalanxAnchorMetrics   *ManipulationMetrics
 	TimestampAnchorMetrics *ManipulationMetrics
 	TimestampBasedMetrics  *ManipulationMetrics
+	ThemisMetrics          *ManipulationMetrics
+	AequitasMetrics        *ManipulationMetrics
 	CommitmentMetrics      *CommitmentMetrics
 }
 
@@ -20,6 +22,8 @@ func NewMetrics() *Metrics {
 		PhalanxAnchorMetrics:   NewManipulationMetrics(),
 		TimestampAnchorMetrics: NewManipulationMetrics(),
 		TimestampBasedMetrics:  NewManipulationMetrics(),
+		ThemisMetrics:          NewManipulationMetrics(),
+		AequitasMetrics:        NewManipulationMetrics(),
 		CommitmentMetrics:      NewCommitmentMetrics(),
 	}
 }
@@ -29,6 +33,8 @@ func (ei *Metrics) QueryMetrics() types.MetricsInfo {
 	phalanxOrder := ei.PhalanxAnchorMetrics.QueryMetrics()
 	mediumTOrder := ei.TimestampBasedMetrics.QueryMetrics()
 	timeAnchorOrder := ei.TimestampAnchorMetrics.QueryMetrics()
+	themisOrder := ei.ThemisMetrics.QueryMetrics()
+	aequitasOrder := ei.AequitasMetrics.QueryMetrics()
 	return types.MetricsInfo{
 		AvePackOrderLatency:       ei.MetaPoolMetrics.AvePackOrderLatency(),
 		AveOrderLatency:           ei.MetaPoolMetrics.AveOrderLatency(),
@@ -38,6 +44,10 @@ func (ei *Metrics) QueryMetrics() types.MetricsInfo {
 		CommandPS:                 ei.MetaPoolMetrics.CommandThroughput(),
 		LogPS:                     ei.MetaPoolMetrics.LogThroughput(),
 		GenLogPS:                  ei.MetaPoolMetrics.GenLogThroughput(),
+		RejectedPreOrders:         ei.MetaPoolMetrics.RejectedPreOrderCount(),
+		RejectedCommands:          ei.MetaPoolMetrics.RejectedCommandCount(),
+		StalledClients:            ei.MetaPoolMetrics.StalledClientCount(),
+		SkippedCommands:           ei.MetaPoolMetrics.SkippedCommandCount(),
 		AveLogLatency:             ei.ExecutorMetrics.AveLogLatency(),
 		CurLogLatency:             ei.ExecutorMetrics.CurLogLatency(),
 		AveCommitStreamLatency:    ei.ExecutorMetrics.AveCommitStreamLatency(),
@@ -51,6 +61,8 @@ func (ei *Metrics) QueryMetrics() types.MetricsInfo {
 		FrontAttackIntervalRisk:   phalanxOrder.FrontAttackIntervalRisk,
 		FrontAttackIntervalSafe:   phalanxOrder.FrontAttackIntervalSafe,
 		SuccessRates:              phalanxOrder.SuccessRates,
+		CycleCount:                phalanxOrder.CycleCount,
+		AveCycleSize:              phalanxOrder.AveCycleSize,
 		MSafeCommandCount:         mediumTOrder.SafeCommandCount,
 		MRiskCommandCount:         mediumTOrder.RiskCommandCount,
 		MFrontAttackFromRisk:      mediumTOrder.FrontAttackFromRisk,
@@ -65,5 +77,19 @@ func (ei *Metrics) QueryMetrics() types.MetricsInfo {
 		TAFrontAttackIntervalRisk: timeAnchorOrder.FrontAttackIntervalRisk,
 		TAFrontAttackIntervalSafe: timeAnchorOrder.FrontAttackIntervalSafe,
 		TASuccessRates:            timeAnchorOrder.SuccessRates,
+		THSafeCommandCount:        themisOrder.SafeCommandCount,
+		THRiskCommandCount:        themisOrder.RiskCommandCount,
+		THFrontAttackFromRisk:     themisOrder.FrontAttackFromRisk,
+		THFrontAttackFromSafe:     themisOrder.FrontAttackFromSafe,
+		THFrontAttackIntervalRisk: themisOrder.FrontAttackIntervalRisk,
+		THFrontAttackIntervalSafe: themisOrder.FrontAttackIntervalSafe,
+		THSuccessRates:            themisOrder.SuccessRates,
+		AQSafeCommandCount:        aequitasOrder.SafeCommandCount,
+		AQRiskCommandCount:        aequitasOrder.RiskCommandCount,
+		AQFrontAttackFromRisk:     aequitasOrder.FrontAttackFromRisk,
+		AQFrontAttackFromSafe:     aequitasOrder.FrontAttackFromSafe,
+		AQFrontAttackIntervalRisk: aequitasOrder.FrontAttackIntervalRisk,
+		AQFrontAttackIntervalSafe: aequitasOrder.FrontAttackIntervalSafe,
+		AQSuccessRates:            aequitasOrder.SuccessRates,
 	}
 }
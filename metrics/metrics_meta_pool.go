@@ -49,6 +49,18 @@ type MetaPoolMetrics struct {
 
 	//
 	GenOrder int
+
+	// RejectedPreOrders is the number of pre-orders we have refused to vote for with invalid timestamps.
+	RejectedPreOrders int
+
+	// RejectedCommands is the number of commands we have refused without a valid client signature.
+	RejectedCommands int
+
+	// StalledClients is the number of clients stalled by missing commands.
+	StalledClients int
+
+	// SkippedCommands is the number of missing commands we have skipped.
+	SkippedCommands int
 }
 
 func NewMetaPoolMetrics() *MetaPoolMetrics {
@@ -84,6 +96,62 @@ func (m *MetaPoolMetrics) GenerateOrder() {
 	m.GenOrder++
 }
 
+func (m *MetaPoolMetrics) RejectPreOrder() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.RejectedPreOrders++
+}
+
+func (m *MetaPoolMetrics) RejectedPreOrderCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.RejectedPreOrders
+}
+
+func (m *MetaPoolMetrics) RejectCommand() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.RejectedCommands++
+}
+
+func (m *MetaPoolMetrics) RejectedCommandCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.RejectedCommands
+}
+
+func (m *MetaPoolMetrics) UpdateStalledClients(count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.StalledClients = count
+}
+
+func (m *MetaPoolMetrics) StalledClientCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.StalledClients
+}
+
+func (m *MetaPoolMetrics) SkipCommand() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.SkippedCommands++
+}
+
+func (m *MetaPoolMetrics) SkippedCommandCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.SkippedCommands
+}
+
 func (m *MetaPoolMetrics) PartialOrderQuorum(pOrder *protos.PartialOrder) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
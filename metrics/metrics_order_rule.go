@@ -33,6 +33,14 @@ type ManipulationMetrics struct {
 	// FrontAttackIntervalRisk is used to record the front attacked command request with risk of interval relationship.
 	FrontAttackIntervalRisk int
 
+	//======================================== precedence cycles =======================================================
+
+	// TotalCycles tracks the number of precedence cycles.
+	TotalCycles int
+
+	// TotalCycleSize tracks the number of commands in precedence cycles.
+	TotalCycleSize int
+
 	//
 	SnappingUpMetrics *SnappingUpMetrics
 }
@@ -64,9 +72,20 @@ func (m *ManipulationMetrics) QueryMetrics() types.MetricsInfo {
 		FrontAttackIntervalRisk: m.FrontAttackIntervalRisk,
 		FrontAttackIntervalSafe: m.FrontAttackIntervalSafe,
 		SuccessRates:            m.SnappingUpMetrics.SuccessRates(),
+		CycleCount:              m.TotalCycles,
+		AveCycleSize:            m.aveCycleSize(),
 	}
 }
 
+// CommitCycle records a precedence cycle with size commands.
+func (m *ManipulationMetrics) CommitCycle(size int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.TotalCycles++
+	m.TotalCycleSize += size
+}
+
 func (m *ManipulationMetrics) DetectFrontSetTypes(risk bool) {
 	if !risk {
 		m.TotalSafeCommit++
@@ -111,3 +130,10 @@ func (m *ManipulationMetrics) UpdateFrontAttackDetector(command *protos.Command)
 		m.CommandRecorder[command.Author] = command.Sequence
 	}
 }
+
+func (m *ManipulationMetrics) aveCycleSize() float64 {
+	if m.TotalCycles == 0 {
+		return 0
+	}
+	return float64(m.TotalCycleSize) / float64(m.TotalCycles)
+}
@@ -1,6 +1,11 @@
 package receiver
 
-import "github.com/Grivn/phalanx/external"
+import (
+	"fmt"
+
+	"github.com/Grivn/phalanx/common/types"
+	"github.com/Grivn/phalanx/external"
+)
 
 type Config struct {
 	Auction     bool
@@ -8,7 +13,24 @@ type Config struct {
 	Multi       int
 	CommandSize int
 	MemSize     int
+	QueueSize   int
+	Overload    string
 	Selected    uint64
+	Hasher      types.Hasher
+	Signers     map[uint64]external.PrivateKey
 	Sender      external.NetworkService
 	Logger      external.Logger
 }
+
+// CheckSigners checks if there is a private key to sign the commands for each client proposed by current node,
+// which is required once the client authentication has been enabled by meta pool.
+func CheckSigners(conf Config) error {
+	base := int(conf.Author-1) * conf.Multi
+	for i := base; i < base+conf.Multi; i++ {
+		id := uint64(i + 1)
+		if conf.Signers[id] == nil {
+			return fmt.Errorf("missing signer of client %d", id)
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package receiver
 import (
 	"github.com/Grivn/phalanx/common/api"
 	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
 	"github.com/Grivn/phalanx/external"
 )
 
@@ -26,6 +27,9 @@ type snappingUpManagerImpl struct {
 }
 
 func NewSnappingUpManager(conf Config) api.Proposer {
+	if conf.Hasher == nil {
+		conf.Hasher = types.DefaultHasher()
+	}
 	buyers := make(map[uint64]*buyerImpl)
 	base := int(conf.Author-1) * conf.Multi
 	for i := base; i < base+conf.Multi; i++ {
@@ -53,6 +57,7 @@ func (su *snappingUpManagerImpl) Quit() {
 	}
 }
 
-func (su *snappingUpManagerImpl) ProcessTransaction(tx *protos.Transaction) {
+func (su *snappingUpManagerImpl) ProcessTransaction(tx *protos.Transaction) error {
 	// no use.
+	return nil
 }
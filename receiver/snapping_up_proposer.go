@@ -13,6 +13,10 @@ type buyerImpl struct {
 
 	itemNo uint64
 
+	hasher types.Hasher
+
+	signer external.PrivateKey
+
 	timer *localTimer
 
 	snappingUpC chan bool
@@ -29,6 +33,8 @@ func newBuyer(id uint64, conf Config) *buyerImpl {
 	return &buyerImpl{
 		id:          id,
 		itemNo:      uint64(0),
+		hasher:      conf.Hasher,
+		signer:      conf.Signers[id],
 		timer:       newLocalTimer(snappingUpC),
 		snappingUpC: snappingUpC,
 		closeC:      make(chan bool),
@@ -73,7 +79,11 @@ func (b *buyerImpl) snappingUp() {
 		return
 	}
 	b.itemNo++
-	command := types.GenerateCommand(b.id, b.itemNo, nil)
+	command := types.GenerateCommand(b.hasher, b.id, b.itemNo, nil)
+	if err := signCommand(b.signer, command); err != nil {
+		b.logger.Errorf("[%d] sign command %s error: %s", b.id, command.FormatSnappingUp(), err)
+		return
+	}
 	b.logger.Infof("[%d] generate command %s", b.id, command.FormatSnappingUp())
 	b.sender.BroadcastCommand(command)
 }
@@ -3,6 +3,7 @@ package receiver
 import (
 	"github.com/Grivn/phalanx/common/api"
 	"github.com/Grivn/phalanx/common/protos"
+	"github.com/Grivn/phalanx/common/types"
 	"github.com/Grivn/phalanx/external"
 )
 
@@ -19,7 +20,10 @@ type txManager struct {
 	proposers map[uint64]*proposerImpl
 
 	// txC is used to submit transactions.
-	txC chan<- *protos.Transaction
+	txC chan *protos.Transaction
+
+	// overload is the policy to process the transaction once txC is full.
+	overload string
 
 	//======================================= external interfaces ==================================================
 
@@ -28,9 +32,15 @@ type txManager struct {
 }
 
 func NewTxManager(conf Config) api.Proposer {
+	if conf.Hasher == nil {
+		conf.Hasher = types.DefaultHasher()
+	}
 	proposers := make(map[uint64]*proposerImpl)
 
-	txC := make(chan *protos.Transaction)
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = types.DefaultQueueSize
+	}
+	txC := make(chan *protos.Transaction, conf.QueueSize)
 
 	base := int(conf.Author-1) * conf.Multi
 
@@ -40,7 +50,7 @@ func NewTxManager(conf Config) api.Proposer {
 		proposers[id] = proposer
 	}
 
-	return &txManager{author: conf.Author, proposers: proposers, txC: txC, logger: conf.Logger}
+	return &txManager{author: conf.Author, proposers: proposers, txC: txC, overload: conf.Overload, logger: conf.Logger}
 }
 
 func (txMgr *txManager) Run() {
@@ -55,6 +65,32 @@ func (txMgr *txManager) Quit() {
 	}
 }
 
-func (txMgr *txManager) ProcessTransaction(tx *protos.Transaction) {
-	txMgr.txC <- tx
+func (txMgr *txManager) ProcessTransaction(tx *protos.Transaction) error {
+	switch txMgr.overload {
+	case types.OverloadReject:
+		select {
+		case txMgr.txC <- tx:
+			return nil
+		default:
+			txMgr.logger.Errorf("[%d] transaction queue is full, reject transaction %s", txMgr.author, tx.Hash)
+			return types.ErrOverload
+		}
+	case types.OverloadDropOldest:
+		for {
+			select {
+			case txMgr.txC <- tx:
+				return nil
+			default:
+			}
+
+			select {
+			case dropped := <-txMgr.txC:
+				txMgr.logger.Errorf("[%d] transaction queue is full, drop transaction %s", txMgr.author, dropped.Hash)
+			default:
+			}
+		}
+	default:
+		txMgr.txC <- tx
+		return nil
+	}
 }
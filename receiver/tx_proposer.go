@@ -23,6 +23,12 @@ type proposerImpl struct {
 	// txSet is used to cache the commands current node has received.
 	txSet []*protos.Transaction
 
+	// hasher is used to calculate the digests of commands.
+	hasher types.Hasher
+
+	// signer is used to sign the commands as current client, and the commands are unsigned without it.
+	signer external.PrivateKey
+
 	// txC is used to receive transactions.
 	txC <-chan *protos.Transaction
 
@@ -48,6 +54,8 @@ func newProposer(author uint64, txC chan *protos.Transaction, conf Config) *prop
 	return &proposerImpl{
 		author:      author,
 		commandSize: conf.CommandSize,
+		hasher:      conf.Hasher,
+		signer:      conf.Signers[author],
 		txC:         txC,
 		closeC:      make(chan bool),
 		sender:      conf.Sender,
@@ -84,9 +92,29 @@ func (p *proposerImpl) processTx(tx *protos.Transaction) {
 	p.txSet = append(p.txSet, tx)
 	if len(p.txSet) == p.commandSize {
 		p.seqNo++
-		command := types.GenerateCommand(p.author, p.seqNo, p.txSet)
+		command := types.GenerateCommand(p.hasher, p.author, p.seqNo, p.txSet)
+		p.txSet = nil
+		if err := signCommand(p.signer, command); err != nil {
+			p.logger.Errorf("[%d] sign command %s error: %s", p.author, command.Format(), err)
+			return
+		}
 		p.sender.BroadcastCommand(command)
 		p.logger.Infof("[%d] generate command %s", p.author, command.Format())
-		p.txSet = nil
 	}
 }
+
+// signCommand signs the digest of command with the private key of its client, which would be verified by meta pool
+// with the registered public key of client.
+func signCommand(signer external.PrivateKey, command *protos.Command) error {
+	if signer == nil {
+		// the client authentication is disabled.
+		return nil
+	}
+
+	signature, err := signer.Sign(types.StringToBytes(command.Digest))
+	if err != nil {
+		return err
+	}
+	command.Signature = signature
+	return nil
+}
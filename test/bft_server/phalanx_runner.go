@@ -55,21 +55,37 @@ func phalanxRunner() {
 			Byz:         byz,
 			OpenLatency: 0,
 			Duration:    types.DefaultTimeDuration,
+			BatchMode:   types.BatchFixed,
 			Interval:    types.DefaultInterval,
 			CDuration:   types.DefaultTimeDuration,
 			N:           n,
 			Multi:       types.DefaultMulti,
 			LogCount:    types.DefaultLogCount,
 			MemSize:     types.DefaultMemSize,
+			QueueSize:   types.DefaultQueueSize,
+			StreamSize:  types.DefaultStreamCacheSize,
+			Strategy:    types.StrategyPhalanxAnchor,
+			Shadows:     []string{types.StrategyTimestampAnchor, types.StrategyTimestampBased, types.StrategyThemis, types.StrategyAequitas},
+			Gamma:       types.DefaultFairnessGamma,
+			Overload:    types.OverloadBlock,
 			CommandSize: types.SingleCommandSize,
 			Selected:    1,
+			Hasher:      types.HashSHA256,
+			Window:      types.DefaultPipelineWindow,
+			Timestamp:   types.NewDefaultTimestampPolicy(),
+			CmdLimit:    types.NewDefaultCommandLimit(),
+			CacheSize:   types.DefaultVerifyCacheSize,
 			PrivateKey:  privKey,
 			PublicKeys:  pubKeys,
+			KeyDecoder:  mocks.NewKeyDecoder(),
 			Exec:        exec,
 			Network:     net,
 			Logger:      logger,
 		}
-		phx[id] = phalanx.NewPhalanxProvider(conf)
+		phx[id], err = phalanx.NewPhalanxProvider(conf)
+		if err != nil {
+			panic(fmt.Sprintf("generate phalanx error: %s", err))
+		}
 		phx[id].Run()
 	}
 
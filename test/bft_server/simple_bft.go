@@ -109,7 +109,7 @@ func (replica *replica) runningProposal() {
 			replica.sequence++
 			replica.aggMap[replica.sequence] = 0
 			pBatch := &protos.PartialOrderBatch{}
-			prop := &bftMessage{from: replica.author, to: 0, typ: proposal, sequence: replica.sequence, digest: types.CalculateBatchHash(pBatch), pBatch: pBatch}
+			prop := &bftMessage{from: replica.author, to: 0, typ: proposal, sequence: replica.sequence, digest: types.CalculateBatchHash(types.DefaultHasher(), pBatch), pBatch: pBatch}
 			replica.logger.Infof("[%d] generate proposal sequence %d, hash %s", replica.author, prop.sequence, prop.digest)
 			replica.sendC <- prop
 			return
@@ -160,7 +160,7 @@ func (replica *replica) propose() *bftMessage {
 
 	replica.sequence++
 	replica.aggMap[replica.sequence] = 0
-	return &bftMessage{from: replica.author, to: 0, typ: proposal, sequence: replica.sequence, digest: types.CalculateBatchHash(pBatch), pBatch: pBatch}
+	return &bftMessage{from: replica.author, to: 0, typ: proposal, sequence: replica.sequence, digest: types.CalculateBatchHash(types.DefaultHasher(), pBatch), pBatch: pBatch}
 }
 
 func (replica *replica) processProposal(message *bftMessage) *bftMessage {
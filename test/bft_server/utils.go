@@ -43,13 +43,13 @@ func phalanxListener(phx phalanx.Provider, net chan *protos.ConsensusMessage, cm
 
 //nolint
 func transactionSender(sender uint64, phx map[uint64]phalanx.Provider) {
-	tx := types.GenerateRandTransaction(1)
+	tx := types.GenerateRandTransaction(types.DefaultHasher(), 1)
 
 	phx[sender].ReceiveTransaction(tx)
 }
 
 func commandSender(sender, seqNo uint64, phx map[uint64]phalanx.Provider) {
-	command := types.GenerateRandCommand(sender, seqNo, 1, 1)
+	command := types.GenerateRandCommand(types.DefaultHasher(), sender, seqNo, 1, 1)
 
 	for _, p := range phx {
 		go p.ReceiveCommand(command)